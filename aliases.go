@@ -0,0 +1,162 @@
+package goisilon
+
+import (
+	"context"
+	"errors"
+	"path"
+
+	api "github.com/thecodeteam/goisilon/api/v2"
+)
+
+type AliasList []*api.Alias
+type Alias *api.Alias
+
+// GetAliases returns a list of all NFS aliases on the cluster, including
+// the health of each alias.
+func (c *Client) GetAliases(ctx context.Context) (AliasList, error) {
+	return api.AliasesList(ctx, c.API, true)
+}
+
+// GetAlias returns the NFS alias with the provided name, including the
+// alias's health.
+func (c *Client) GetAlias(ctx context.Context, name string) (Alias, error) {
+	return api.AliasInspect(ctx, c.API, aliasName(name), true)
+}
+
+// GetAliasHealth returns the health of the NFS alias with the provided name.
+func (c *Client) GetAliasHealth(
+	ctx context.Context, name string) (api.AliasHealth, error) {
+
+	alias, err := c.GetAlias(ctx, name)
+	if err != nil {
+		return api.AliasHealthUnknown, err
+	}
+	if alias == nil || alias.Health == nil {
+		return api.AliasHealthUnknown, nil
+	}
+	return *alias.Health, nil
+}
+
+// CreateAlias creates an NFS alias and returns its ID.
+func (c *Client) CreateAlias(
+	ctx context.Context, alias *api.Alias) (string, error) {
+
+	return api.AliasCreate(ctx, c.API, alias)
+}
+
+// UpdateAlias updates an NFS alias. Only the alias's non-nil fields are
+// modified.
+func (c *Client) UpdateAlias(ctx context.Context, alias *api.Alias) error {
+	return api.AliasUpdate(ctx, c.API, alias)
+}
+
+// DeleteAlias deletes the NFS alias with the provided name.
+func (c *Client) DeleteAlias(ctx context.Context, name string) error {
+	return api.AliasDelete(ctx, c.API, aliasName(name))
+}
+
+// GetAliasesByVolume returns the NFS aliases with a path for the provided
+// volume name.
+func (c *Client) GetAliasesByVolume(
+	ctx context.Context, name string) (AliasList, error) {
+
+	aliases, err := api.AliasesList(ctx, c.API, false)
+	if err != nil {
+		return nil, err
+	}
+	var (
+		volAliases AliasList
+		volPath    = c.API.VolumePath(name)
+	)
+	for _, a := range aliases {
+		if a.Path != nil && *a.Path == volPath {
+			volAliases = append(volAliases, a)
+		}
+	}
+	return volAliases, nil
+}
+
+// AliasOptions are the options used when aliasing a volume.
+type AliasOptions struct {
+
+	// Repoint indicates whether or not an alias that already exists for
+	// another path is updated to point to the volume. If false, an error is
+	// returned instead.
+	Repoint bool
+}
+
+// AliasVolume creates an NFS alias with the provided name for the volume
+// with a given name and returns the alias's ID. If the alias already exists
+// for the volume then its ID is returned. An alias that already exists for
+// another path is not modified and an error is returned.
+func (c *Client) AliasVolume(
+	ctx context.Context, volumeName, alias string) (string, error) {
+
+	return c.AliasVolumeWithOptions(ctx, volumeName, alias, nil)
+}
+
+// AliasVolumeWithOptions creates an NFS alias with the provided name for the
+// volume with a given name and returns the alias's ID. An alias that already
+// exists for another path is only updated to point to the volume if
+// requested.
+func (c *Client) AliasVolumeWithOptions(
+	ctx context.Context,
+	volumeName, alias string,
+	opts *AliasOptions) (string, error) {
+
+	if opts == nil {
+		opts = &AliasOptions{}
+	}
+
+	var (
+		name    = aliasName(alias)
+		volPath = c.API.VolumePath(volumeName)
+	)
+
+	aliases, err := api.AliasesList(ctx, c.API, false)
+	if err != nil {
+		return "", err
+	}
+	for _, a := range aliases {
+		if a.Name == nil || *a.Name != name {
+			continue
+		}
+		if a.Path != nil && path.Clean(*a.Path) == volPath {
+			return a.ID, nil
+		}
+		if !opts.Repoint {
+			return "", errors.New("alias belongs to another path: " + name)
+		}
+		if err := api.AliasUpdate(
+			ctx, c.API, &api.Alias{ID: a.ID, Path: &volPath}); err != nil {
+			return "", err
+		}
+		return a.ID, nil
+	}
+
+	return api.AliasCreate(
+		ctx, c.API,
+		&api.Alias{Name: &name, Path: &volPath})
+}
+
+// UnaliasVolume deletes all of the NFS aliases for the volume with a given
+// name.
+func (c *Client) UnaliasVolume(ctx context.Context, volumeName string) error {
+
+	aliases, err := c.GetAliasesByVolume(ctx, volumeName)
+	if err != nil {
+		return err
+	}
+	for _, a := range aliases {
+		if err := api.AliasDelete(ctx, c.API, a.ID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// aliasName returns the name of an NFS alias, which must be an absolute
+// path, such as "/app1".
+func aliasName(name string) string {
+	return path.Join("/", name)
+}
@@ -0,0 +1,70 @@
+package goisilon
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	apiv2 "github.com/thecodeteam/goisilon/api/v2"
+)
+
+func TestAliasVolume(t *testing.T) {
+	volumeName := "test_alias_volume"
+	aliasName := "/test_alias_volume_alias"
+
+	_, err := client.CreateVolume(defaultCtx, volumeName)
+	assertNoError(t, err)
+	defer client.DeleteVolume(defaultCtx, volumeName)
+
+	_, err = client.ExportVolumeWithOptions(
+		defaultCtx, volumeName, &ExportOptions{Alias: aliasName})
+	assertNoError(t, err)
+	defer client.UnexportVolume(defaultCtx, volumeName)
+	defer client.UnaliasVolume(defaultCtx, volumeName)
+
+	alias, err := client.GetAlias(defaultCtx, aliasName)
+	assertNoError(t, err)
+	assertNotNil(t, alias)
+	assert.Equal(t, client.API.VolumePath(volumeName), *alias.Path)
+
+	health, err := client.GetAliasHealth(defaultCtx, aliasName)
+	assertNoError(t, err)
+	assert.Equal(t, apiv2.AliasHealthGood, health)
+
+	assertNoError(t, client.UnaliasVolume(defaultCtx, volumeName))
+	aliases, err := client.GetAliasesByVolume(defaultCtx, volumeName)
+	assertNoError(t, err)
+	assert.Len(t, aliases, 0)
+}
+
+func TestAliasVolumeOtherPath(t *testing.T) {
+	var updates []map[string]interface{}
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			w.Write([]byte(`{"aliases":[{"id":"/app1","name":"/app1",` +
+				`"path":"/ifs/volumes/other"}]}`))
+		case http.MethodPut:
+			body := map[string]interface{}{}
+			assertNoError(t, json.NewDecoder(r.Body).Decode(&body))
+			updates = append(updates, body)
+			w.Write([]byte(`{}`))
+		default:
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+	}, nil)
+
+	_, err := c.AliasVolume(defaultCtx, "vol1", "app1")
+	assert.Error(t, err)
+	assert.Len(t, updates, 0)
+
+	id, err := c.AliasVolumeWithOptions(
+		defaultCtx, "vol1", "app1", &AliasOptions{Repoint: true})
+	assertNoError(t, err)
+	assert.Equal(t, "/app1", id)
+	if assert.Len(t, updates, 1) {
+		assert.Equal(t, "/ifs/volumes/vol1", updates[0]["path"])
+	}
+}
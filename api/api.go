@@ -6,6 +6,7 @@ import (
 	"crypto/tls"
 	"encoding/base64"
 	"errors"
+	"fmt"
 	"io"
 	"net/http"
 	"net/url"
@@ -26,6 +27,7 @@ const (
 	headerValContentTypeJSON              = "application/json"
 	headerValContentTypeBinaryOctetStream = "binary/octet-stream"
 	defaultVolumesPath                    = "/ifs/volumes"
+	ifsPath                               = "/ifs"
 )
 
 var (
@@ -50,7 +52,9 @@ type Client interface {
 		params OrderedValues, headers map[string]string,
 		body, resp interface{}) error
 
-	// Get sends an HTTP request using the GET method to the OneFS API.
+	// Get sends an HTTP request using the GET method to the OneFS API. If
+	// resp is an io.Writer then the response body is copied to it rather
+	// than decoded as JSON.
 	Get(
 		ctx context.Context,
 		path, id string,
@@ -92,6 +96,15 @@ type Client interface {
 
 	// VolumePath returns the path to a volume with the provided name.
 	VolumePath(name string) string
+
+	// VolumeRoots returns the client's named volume roots and their paths.
+	VolumeRoots() map[string]string
+
+	// WithVolumeRoot returns a copy of the client with its volumes path set
+	// to the provided volume root. The root may be the name of one of the
+	// client's volume roots or an absolute path beneath /ifs. An empty root
+	// selects the client's default volumes path.
+	WithVolumeRoot(root string) (Client, error)
 }
 
 type client struct {
@@ -101,6 +114,8 @@ type client struct {
 	user string
 	grup string
 	volp string
+	dvlp string
+	vrts map[string]string
 	apiv uint8
 }
 
@@ -130,6 +145,10 @@ type ClientOptions struct {
 	// stored.
 	VolumesPath string
 
+	// VolumeRoots is a map of named volume roots to the locations on the
+	// Isilon server where their volumes are stored.
+	VolumeRoots map[string]string
+
 	// Timeout specifies a time limit for requests made by this client.
 	Timeout time.Duration
 }
@@ -159,6 +178,17 @@ func New(
 			c.volp = opts.VolumesPath
 		}
 
+		if len(opts.VolumeRoots) > 0 {
+			c.vrts = map[string]string{}
+			for k, v := range opts.VolumeRoots {
+				p, err := cleanIFSPath(v)
+				if err != nil {
+					return nil, err
+				}
+				c.vrts[k] = p
+			}
+		}
+
 		if opts.Timeout != 0 {
 			c.http.Timeout = opts.Timeout
 		}
@@ -172,6 +202,8 @@ func New(
 		}
 	}
 
+	c.dvlp = c.volp
+
 	resp := &apiVerResponse{}
 	if err := c.Get(ctx, "/platform/latest", "", nil, nil, resp); err != nil &&
 		!strings.HasPrefix(err.Error(), "json: ") {
@@ -312,6 +344,10 @@ func (c *client) DoWithHeaders(
 		if resp == nil {
 			return nil
 		}
+		if w, ok := resp.(io.Writer); ok {
+			_, err = io.Copy(w, res.Body)
+			return err
+		}
 		dec := json.NewDecoder(res.Body)
 		if err = dec.Decode(resp); err != nil && err != io.EOF {
 			return err
@@ -475,6 +511,44 @@ func (c *client) VolumePath(volumeName string) string {
 	return path.Join(c.volp, volumeName)
 }
 
+func (c *client) VolumeRoots() map[string]string {
+	roots := make(map[string]string, len(c.vrts))
+	for k, v := range c.vrts {
+		roots[k] = v
+	}
+	return roots
+}
+
+func (c *client) WithVolumeRoot(root string) (Client, error) {
+	var p string
+	switch {
+	case root == "":
+		p = c.dvlp
+	case beginsWithSlash(root):
+		var err error
+		if p, err = cleanIFSPath(root); err != nil {
+			return nil, err
+		}
+	default:
+		var ok bool
+		if p, ok = c.vrts[root]; !ok {
+			return nil, fmt.Errorf("unknown volume root: %s", root)
+		}
+	}
+	nc := *c
+	nc.volp = p
+	return &nc, nil
+}
+
+// cleanIFSPath returns the cleaned form of an absolute path beneath /ifs.
+func cleanIFSPath(p string) (string, error) {
+	cp := path.Clean(p)
+	if cp != ifsPath && !strings.HasPrefix(cp, ifsPath+"/") {
+		return "", fmt.Errorf("path not beneath %s: %s", ifsPath, p)
+	}
+	return cp, nil
+}
+
 func (err *JSONError) Error() string {
 	return err.Err[0].Message
 }
@@ -52,3 +52,31 @@ func assertNotNil(t *testing.T, i interface{}) {
 		t.FailNow()
 	}
 }
+
+func TestWithVolumeRoot(t *testing.T) {
+	c := &client{
+		volp: "/ifs/volumes",
+		dvlp: "/ifs/volumes",
+		vrts: map[string]string{"gold": "/ifs/gold/volumes"},
+	}
+
+	gc, err := c.WithVolumeRoot("gold")
+	assertNoError(t, err)
+	assert.Equal(t, "/ifs/gold/volumes", gc.VolumesPath())
+	assert.Equal(t, "/ifs/gold/volumes/vol1", gc.VolumePath("vol1"))
+	assert.Equal(t, "/ifs/volumes", c.VolumesPath())
+
+	ac, err := gc.WithVolumeRoot("/ifs/bronze/volumes/")
+	assertNoError(t, err)
+	assert.Equal(t, "/ifs/bronze/volumes", ac.VolumesPath())
+
+	dc, err := ac.WithVolumeRoot("")
+	assertNoError(t, err)
+	assert.Equal(t, "/ifs/volumes", dc.VolumesPath())
+
+	_, err = c.WithVolumeRoot("silver")
+	assertError(t, err)
+
+	_, err = c.WithVolumeRoot("/tmp/volumes")
+	assertError(t, err)
+}
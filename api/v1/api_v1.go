@@ -11,9 +11,13 @@ import (
 
 const (
 	namespacePath       = "namespace"
+	ifsPath             = "/ifs"
 	exportsPath         = "platform/1/protocols/nfs/exports"
 	quotaPath           = "platform/1/quota/quotas"
+	quotaReportsPath    = "platform/1/quota/reports"
 	snapshotsPath       = "platform/1/snapshot/snapshots"
+	snapshotAliasesPath = "platform/1/snapshot/aliases"
+	jobsPath            = "platform/1/job/jobs"
 	volumesnapshotsPath = "/ifs/.snapshot"
 )
 
@@ -29,7 +33,10 @@ func realexportsPath(client api.Client) string {
 	return path.Join(exportsPath, client.VolumesPath())
 }
 
+// realVolumeSnapshotPath returns the namespace path of the client's volumes
+// path inside of the snapshot with the provided name.
 func realVolumeSnapshotPath(client api.Client, name string) string {
-	parts := strings.SplitN(realNamespacePath(client), "/ifs/", 2)
-	return path.Join(parts[0], volumesnapshotsPath, name, parts[1])
+	return path.Join(
+		namespacePath, volumesnapshotsPath, name,
+		strings.TrimPrefix(client.VolumesPath(), ifsPath))
 }
@@ -0,0 +1,68 @@
+package v1
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/thecodeteam/goisilon/api"
+)
+
+// IsiJobStateSucceeded is the state of a job that completed successfully.
+const IsiJobStateSucceeded = "succeeded"
+
+// StartIsiTreeDeleteJob starts a TreeDelete job that removes a directory and
+// all of its descendants from the cluster
+func StartIsiTreeDeleteJob(
+	ctx context.Context,
+	client api.Client,
+	path string) (int, error) {
+
+	// PAPI call: POST https://1.2.3.4:8080/platform/1/job/jobs
+	//            Content-Type: application/json
+	//            {type: "TreeDelete", paths: ["/path/to/volume"]}
+
+	if path == "" {
+		return 0, errors.New("no path set")
+	}
+
+	data := &IsiJobReq{Type: "TreeDelete", Paths: []string{path}}
+
+	var resp postIsiJobResp
+	if err := client.Post(ctx, jobsPath, "", nil, nil, data, &resp); err != nil {
+		return 0, err
+	}
+	return resp.Id, nil
+}
+
+// GetIsiJob queries an individual job on the cluster
+func GetIsiJob(
+	ctx context.Context,
+	client api.Client,
+	id int) (*IsiJob, error) {
+
+	// PAPI call: GET https://1.2.3.4:8080/platform/1/job/jobs/123
+	var resp getIsiJobsResp
+	if err := client.Get(
+		ctx, jobsPath, strconv.Itoa(id), nil, nil, &resp); err != nil {
+		return nil, err
+	}
+	if len(resp.Jobs) == 0 {
+		return nil, fmt.Errorf("Job not found: %d", id)
+	}
+	return resp.Jobs[0], nil
+}
+
+// IsIsiJobDone returns a flag indicating whether or not a job has finished,
+// and an error if the job did not finish successfully.
+func IsIsiJobDone(job *IsiJob) (bool, error) {
+	switch job.State {
+	case IsiJobStateSucceeded:
+		return true, nil
+	case "failed", "failed_not_retried", "cancelled_user",
+		"cancelled_system", "unknown":
+		return true, fmt.Errorf("job %d %s", job.Id, job.State)
+	}
+	return false, nil
+}
@@ -0,0 +1,120 @@
+package v1
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"io"
+
+	"github.com/thecodeteam/goisilon/api"
+)
+
+// IsiQuotaReport describes a quota report generated by the cluster.
+type IsiQuotaReport struct {
+	Id        string `json:"id"`
+	Generated string `json:"generated"`
+	Time      int64  `json:"time"`
+	Type      string `json:"type"`
+}
+
+type isiQuotaReportListResp struct {
+	Reports []*IsiQuotaReport `json:"reports"`
+	Resume  string            `json:"resume"`
+}
+
+// GetIsiQuotaReports queries the quota reports available on the cluster
+func GetIsiQuotaReports(
+	ctx context.Context,
+	client api.Client) ([]*IsiQuotaReport, error) {
+
+	// PAPI call: GET https://1.2.3.4:8080/platform/1/quota/reports
+
+	var (
+		reports []*IsiQuotaReport
+		params  api.OrderedValues
+	)
+	for {
+		var reportsResp isiQuotaReportListResp
+		err := client.Get(ctx, quotaReportsPath, "", params, nil, &reportsResp)
+		if err != nil {
+			return nil, err
+		}
+		reports = append(reports, reportsResp.Reports...)
+		if reportsResp.Resume == "" {
+			return reports, nil
+		}
+		params = api.OrderedValues{{byteArrResume, []byte(reportsResp.Resume)}}
+	}
+}
+
+// GetIsiQuotaReportQuotas downloads a quota report and returns the quotas
+// it contains
+func GetIsiQuotaReportQuotas(
+	ctx context.Context,
+	client api.Client,
+	id string) ([]IsiQuota, error) {
+
+	// PAPI call: GET https://1.2.3.4:8080/platform/1/quota/reports/id
+	// The report data is returned as XML
+
+	buf := &bytes.Buffer{}
+	if err := client.Get(ctx, quotaReportsPath, id, nil, nil, buf); err != nil {
+		return nil, err
+	}
+	return ParseIsiQuotaReport(buf)
+}
+
+type isiQuotaReportXML struct {
+	Domains []isiQuotaReportDomainXML `xml:"domains>domain"`
+}
+
+type isiQuotaReportDomainXML struct {
+	Type                      string `xml:"type,attr"`
+	Path                      string `xml:"path,attr"`
+	IncludeSnapshots          bool   `xml:"include-snapshots,attr"`
+	ThresholdsIncludeOverhead bool   `xml:"thresholds-include-overhead,attr"`
+	Enforced                  bool   `xml:"enforced,attr"`
+	Container                 bool   `xml:"container,attr"`
+	Usage                     struct {
+		Inodes   int64 `xml:"inodes,attr"`
+		Logical  int64 `xml:"logical,attr"`
+		Physical int64 `xml:"physical,attr"`
+	} `xml:"usage"`
+	Advisory isiQuotaReportThresholdXML `xml:"advisory-threshold"`
+	Soft     isiQuotaReportThresholdXML `xml:"soft-threshold"`
+	Hard     isiQuotaReportThresholdXML `xml:"hard-threshold"`
+}
+
+type isiQuotaReportThresholdXML struct {
+	Limit    int64 `xml:",chardata"`
+	Exceeded bool  `xml:"exceeded,attr"`
+}
+
+// ParseIsiQuotaReport decodes the domains of a quota report's XML data into
+// quotas. Thresholds missing from the report are left at zero.
+func ParseIsiQuotaReport(r io.Reader) ([]IsiQuota, error) {
+	var report isiQuotaReportXML
+	if err := xml.NewDecoder(r).Decode(&report); err != nil {
+		return nil, err
+	}
+	quotas := make([]IsiQuota, len(report.Domains))
+	for i, d := range report.Domains {
+		q := &quotas[i]
+		q.Type = d.Type
+		q.Path = d.Path
+		q.IncludeSnapshots = d.IncludeSnapshots
+		q.ThresholdsIncludeOverhead = d.ThresholdsIncludeOverhead
+		q.Enforced = d.Enforced
+		q.Container = d.Container
+		q.Usage.Inodes = d.Usage.Inodes
+		q.Usage.Logical = d.Usage.Logical
+		q.Usage.Physical = d.Usage.Physical
+		q.Thresholds.Advisory = d.Advisory.Limit
+		q.Thresholds.AdvisoryExceeded = d.Advisory.Exceeded
+		q.Thresholds.Soft = d.Soft.Limit
+		q.Thresholds.SoftExceeded = d.Soft.Exceeded
+		q.Thresholds.Hard = d.Hard.Limit
+		q.Thresholds.HardExceeded = d.Hard.Exceeded
+	}
+	return quotas, nil
+}
@@ -14,25 +14,74 @@ func GetIsiQuota(
 	client api.Client,
 	path string) (quota *IsiQuota, err error) {
 
-	// PAPI call: GET https://1.2.3.4:8080/platform/1/quota/quotas
-	// This will list out all quotas on the cluster
+	// PAPI call: GET https://1.2.3.4:8080/platform/1/quota/quotas?path=/path/to/volume&type=directory
 
-	var quotaResp isiQuotaListResp
-	err = client.Get(ctx, quotaPath, "", nil, nil, &quotaResp)
+	quotas, err := getIsiQuotas(ctx, client, api.OrderedValues{
+		{byteArrPath, []byte(path)},
+		{byteArrType, byteArrDirectory},
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	// find the specific quota we are looking for
-	for _, quota := range quotaResp.Quotas {
+	for _, quota := range quotas {
 		if quota.Path == path {
-			return &quota, nil
+			return quota, nil
 		}
 	}
 
 	return nil, errors.New(fmt.Sprintf("Quota not found: %s", path))
 }
 
+// GetIsiQuotasByPath queries the quotas of all types for a directory. If
+// recurse is true then the quotas of the directory's descendants are
+// included as well.
+func GetIsiQuotasByPath(
+	ctx context.Context,
+	client api.Client,
+	path string, recurse bool) ([]*IsiQuota, error) {
+
+	// PAPI call: GET https://1.2.3.4:8080/platform/1/quota/quotas?path=/path/to/volume
+
+	params := api.OrderedValues{{byteArrPath, []byte(path)}}
+	if recurse {
+		params = append(
+			params, [][]byte{byteArrRecursePathChildren, byteArrTrue})
+	}
+	return getIsiQuotas(ctx, client, params)
+}
+
+var (
+	byteArrType                = []byte("type")
+	byteArrDirectory           = []byte("directory")
+	byteArrRecursePathChildren = []byte("recurse_path_children")
+	byteArrTrue                = []byte("true")
+	byteArrResume              = []byte("resume")
+)
+
+// getIsiQuotas pages through the quotas that match the provided query
+func getIsiQuotas(
+	ctx context.Context,
+	client api.Client,
+	params api.OrderedValues) ([]*IsiQuota, error) {
+
+	var quotas []*IsiQuota
+	for {
+		var quotaResp isiQuotaListResp
+		err := client.Get(ctx, quotaPath, "", params, nil, &quotaResp)
+		if err != nil {
+			return nil, err
+		}
+		for i := range quotaResp.Quotas {
+			quotas = append(quotas, &quotaResp.Quotas[i])
+		}
+		if quotaResp.Resume == "" {
+			return quotas, nil
+		}
+		params = api.OrderedValues{{byteArrResume, []byte(quotaResp.Resume)}}
+	}
+}
+
 // TODO: Add a means to set/update more than just the hard threshold
 
 // SetIsiQuotaHardThreshold sets the hard threshold of a quota for a directory
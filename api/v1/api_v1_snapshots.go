@@ -4,7 +4,10 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"net/http"
+	"net/url"
 	"path"
+	"strconv"
 
 	"github.com/thecodeteam/goisilon/api"
 )
@@ -14,28 +17,62 @@ func GetIsiSnapshots(
 	ctx context.Context,
 	client api.Client) (resp *getIsiSnapshotsResp, err error) {
 	// PAPI call: GET https://1.2.3.4:8080/platform/1/snapshot/snapshots
-	err = client.Get(ctx, snapshotsPath, "", nil, nil, &resp)
-	if err != nil {
-		return nil, err
+	var params api.OrderedValues
+	for {
+		var page *getIsiSnapshotsResp
+		err = client.Get(ctx, snapshotsPath, "", params, nil, &page)
+		if err != nil {
+			return nil, err
+		}
+		if page == nil {
+			page = &getIsiSnapshotsResp{}
+		}
+		if resp == nil {
+			resp = page
+		} else {
+			resp.SnapshotList = append(resp.SnapshotList, page.SnapshotList...)
+		}
+		if page.Resume == "" {
+			resp.Resume = ""
+			return resp, nil
+		}
+		params = api.OrderedValues{{byteArrResume, []byte(page.Resume)}}
 	}
-	return resp, nil
 }
 
-// GetIsiSnapshot queries an individual snapshot on the cluster
+// GetIsiSnapshot queries an individual snapshot on the cluster. A nil
+// snapshot is returned if the cluster returns an empty list
 func GetIsiSnapshot(
 	ctx context.Context,
 	client api.Client,
 	id int64) (*IsiSnapshot, error) {
+	return GetIsiSnapshotByIDOrName(ctx, client, strconv.FormatInt(id, 10))
+}
+
+// GetIsiSnapshotByIDOrName queries an individual snapshot on the cluster by
+// its ID or name. A nil snapshot is returned if the cluster returns an empty
+// list
+func GetIsiSnapshotByIDOrName(
+	ctx context.Context,
+	client api.Client,
+	idOrName string) (*IsiSnapshot, error) {
 	// PAPI call: GET https://1.2.3.4:8080/platform/1/snapshot/snapshots/123
-	snapshotUrl := fmt.Sprintf("%s/%d", snapshotsPath, id)
+	//            GET https://1.2.3.4:8080/platform/1/snapshot/snapshots/name
+	if idOrName == "" {
+		return nil, errors.New("no snapshot id or name set")
+	}
 	var resp *getIsiSnapshotsResp
-	err := client.Get(ctx, snapshotUrl, "", nil, nil, &resp)
+	err := client.Get(
+		ctx, snapshotsPath, url.PathEscape(idOrName), nil, nil, &resp)
 	if err != nil {
 		return nil, err
 	}
 	// PAPI returns the snapshot data in a JSON list with the same structure as
 	// when querying all snapshots.  Since this is for a single Id, we just
 	// want the first (and should be only) entry in the list.
+	if resp == nil || len(resp.SnapshotList) == 0 {
+		return nil, nil
+	}
 	return resp.SnapshotList[0], nil
 }
 
@@ -49,13 +86,25 @@ func CreateIsiSnapshot(
 	//            {path: "/path/to/volume"
 	//             name: "snapshot_name"  <--- optional
 	//            }
-	if path == "" {
-		return nil, errors.New("no path set")
-	}
+	return CreateIsiSnapshotWithOptions(
+		ctx, client, &SnapshotPath{Path: path, Name: name})
+}
 
-	data := &SnapshotPath{Path: path}
-	if name != "" {
-		data.Name = name
+// CreateIsiSnapshotWithOptions makes a new snapshot on the cluster with an
+// optional expiration time and alias
+func CreateIsiSnapshotWithOptions(
+	ctx context.Context,
+	client api.Client,
+	data *SnapshotPath) (resp *IsiSnapshot, err error) {
+	// PAPI call: POST https://1.2.3.4:8080/platform/1/snapshot/snapshots
+	//            Content-Type: application/json
+	//            {path: "/path/to/volume"
+	//             name: "snapshot_name"  <--- optional
+	//             expires: 1500000000    <--- optional
+	//             alias: "alias_name"    <--- optional
+	//            }
+	if data.Path == "" {
+		return nil, errors.New("no path set")
 	}
 
 	err = client.Post(ctx, snapshotsPath, "", nil, nil, data, &resp)
@@ -65,6 +114,17 @@ func CreateIsiSnapshot(
 	return resp, nil
 }
 
+// UpdateIsiSnapshot renames a snapshot or changes its expiration time
+func UpdateIsiSnapshot(
+	ctx context.Context,
+	client api.Client,
+	id int64, data *IsiSnapshotUpdateReq) error {
+	// PAPI call: PUT https://1.2.3.4:8080/platform/1/snapshot/snapshots/123
+	//            {name: "snapshot_name", expires: 1500000000}
+	snapshotUrl := fmt.Sprintf("%s/%d", snapshotsPath, id)
+	return client.Put(ctx, snapshotUrl, "", nil, nil, data, nil)
+}
+
 // CopyIsiSnaphost copies all files/directories in a snapshot to a new directory
 func CopyIsiSnapshot(
 	ctx context.Context,
@@ -97,3 +157,167 @@ func RemoveIsiSnapshot(
 
 	return err
 }
+
+// snapshotLocksPath returns the path of the locks of a snapshot
+func snapshotLocksPath(id int64) string {
+	return fmt.Sprintf("%s/%d/locks", snapshotsPath, id)
+}
+
+// GetIsiSnapshotLocks queries the locks of a snapshot
+func GetIsiSnapshotLocks(
+	ctx context.Context,
+	client api.Client,
+	id int64) ([]*IsiSnapshotLock, error) {
+	// PAPI call: GET https://1.2.3.4:8080/platform/1/snapshot/snapshots/123/locks
+	var (
+		locks  []*IsiSnapshotLock
+		params api.OrderedValues
+	)
+	for {
+		var resp getIsiSnapshotLocksResp
+		err := client.Get(ctx, snapshotLocksPath(id), "", params, nil, &resp)
+		if err != nil {
+			return nil, err
+		}
+		locks = append(locks, resp.Locks...)
+		if resp.Resume == "" {
+			return locks, nil
+		}
+		params = api.OrderedValues{{byteArrResume, []byte(resp.Resume)}}
+	}
+}
+
+// CreateIsiSnapshotLock locks a snapshot so that it cannot be deleted and
+// returns the ID of the lock. A lock without an expiration time never
+// expires
+func CreateIsiSnapshotLock(
+	ctx context.Context,
+	client api.Client,
+	id int64, comment string, expires int64) (int64, error) {
+	// PAPI call: POST https://1.2.3.4:8080/platform/1/snapshot/snapshots/123/locks
+	//            {comment: "backup in progress", expires: 1500000000}
+	data := &IsiSnapshotLock{Comment: comment}
+	if expires != 0 {
+		data.Expires = &expires
+	}
+	var resp IsiSnapshotLock
+	err := client.Post(ctx, snapshotLocksPath(id), "", nil, nil, data, &resp)
+	if err != nil {
+		return 0, err
+	}
+	return resp.Id, nil
+}
+
+// UpdateIsiSnapshotLock changes the expiration time of a snapshot lock. An
+// expiration time of zero is sent as is, so that the lock never expires
+func UpdateIsiSnapshotLock(
+	ctx context.Context,
+	client api.Client,
+	id, lockID int64, expires int64) error {
+	// PAPI call: PUT https://1.2.3.4:8080/platform/1/snapshot/snapshots/123/locks/1
+	//            {expires: 1500000000}
+	return client.Put(
+		ctx, snapshotLocksPath(id), strconv.FormatInt(lockID, 10), nil, nil,
+		&IsiSnapshotLock{Expires: &expires}, nil)
+}
+
+// RemoveIsiSnapshotLock deletes a snapshot lock
+func RemoveIsiSnapshotLock(
+	ctx context.Context,
+	client api.Client,
+	id, lockID int64) error {
+	// PAPI call: DELETE https://1.2.3.4:8080/platform/1/snapshot/snapshots/123/locks/1
+	return client.Delete(
+		ctx, snapshotLocksPath(id), strconv.FormatInt(lockID, 10),
+		nil, nil, nil)
+}
+
+// GetIsiSnapshotAliases queries all of the snapshot aliases on the cluster
+func GetIsiSnapshotAliases(
+	ctx context.Context,
+	client api.Client) ([]*IsiSnapshotAlias, error) {
+	// PAPI call: GET https://1.2.3.4:8080/platform/1/snapshot/aliases
+	var (
+		aliases []*IsiSnapshotAlias
+		params  api.OrderedValues
+	)
+	for {
+		var resp getIsiSnapshotAliasesResp
+		err := client.Get(ctx, snapshotAliasesPath, "", params, nil, &resp)
+		if err != nil {
+			return nil, err
+		}
+		aliases = append(aliases, resp.Aliases...)
+		if resp.Resume == "" {
+			return aliases, nil
+		}
+		params = api.OrderedValues{{byteArrResume, []byte(resp.Resume)}}
+	}
+}
+
+// GetIsiSnapshotAlias queries a snapshot alias by its ID or name. A 404
+// *api.JSONError is returned if the cluster returns an empty list
+func GetIsiSnapshotAlias(
+	ctx context.Context,
+	client api.Client,
+	name string) (*IsiSnapshotAlias, error) {
+	// PAPI call: GET https://1.2.3.4:8080/platform/1/snapshot/aliases/name
+	var resp getIsiSnapshotAliasesResp
+	err := client.Get(
+		ctx, snapshotAliasesPath, url.PathEscape(name), nil, nil, &resp)
+	if err != nil {
+		return nil, err
+	}
+	if len(resp.Aliases) == 0 {
+		return nil, &api.JSONError{
+			StatusCode: http.StatusNotFound,
+			Err: []api.Error{
+				{Message: "Snapshot alias not found: " + name},
+			},
+		}
+	}
+	return resp.Aliases[0], nil
+}
+
+// CreateIsiSnapshotAlias creates an alias that refers to the target
+// snapshot, given by its ID or name, and returns the alias's ID
+func CreateIsiSnapshotAlias(
+	ctx context.Context,
+	client api.Client,
+	name, target string) (int64, error) {
+	// PAPI call: POST https://1.2.3.4:8080/platform/1/snapshot/aliases
+	//            {name: "alias_name", target: "snapshot_name"}
+	if name == "" {
+		return 0, errors.New("no name set")
+	}
+	var resp IsiSnapshotAlias
+	err := client.Post(
+		ctx, snapshotAliasesPath, "", nil, nil,
+		&isiSnapshotAliasReq{Name: name, Target: target}, &resp)
+	if err != nil {
+		return 0, err
+	}
+	return resp.Id, nil
+}
+
+// UpdateIsiSnapshotAlias changes the snapshot that an alias refers to
+func UpdateIsiSnapshotAlias(
+	ctx context.Context,
+	client api.Client,
+	name, target string) error {
+	// PAPI call: PUT https://1.2.3.4:8080/platform/1/snapshot/aliases/name
+	//            {target: "snapshot_name"}
+	return client.Put(
+		ctx, snapshotAliasesPath, url.PathEscape(name), nil, nil,
+		&isiSnapshotAliasReq{Target: target}, nil)
+}
+
+// RemoveIsiSnapshotAlias deletes a snapshot alias by its ID or name
+func RemoveIsiSnapshotAlias(
+	ctx context.Context,
+	client api.Client,
+	name string) error {
+	// PAPI call: DELETE https://1.2.3.4:8080/platform/1/snapshot/aliases/name
+	return client.Delete(
+		ctx, snapshotAliasesPath, url.PathEscape(name), nil, nil, nil)
+}
@@ -17,6 +17,20 @@ type getIsiVolumesResp struct {
 	Children []*VolumeName `json:"children"`
 }
 
+// Isi PAPI volume copy error JSON struct
+type IsiCopyError struct {
+	Source   string `json:"source"`
+	Target   string `json:"target"`
+	ErrorSrc string `json:"error_src"`
+	Message  string `json:"message"`
+}
+
+// Isi PAPI volume copy response JSON struct
+type IsiCopyResp struct {
+	Success    bool            `json:"success"`
+	CopyErrors []*IsiCopyError `json:"copy_errors"`
+}
+
 // Isi PAPI Volume ACL JSON structs
 type Ownership struct {
 	Name string `json:"name"`
@@ -70,8 +84,49 @@ type getIsiExportsResp struct {
 
 // Isi PAPI snapshot path JSON struct
 type SnapshotPath struct {
-	Path string `json:"path"`
-	Name string `json:"name,omitempty"`
+	Path    string `json:"path"`
+	Name    string `json:"name,omitempty"`
+	Expires int64  `json:"expires,omitempty"`
+	Alias   string `json:"alias,omitempty"`
+}
+
+// Isi PAPI snapshot update JSON struct. A non-nil Expires of zero clears the
+// snapshot's expiration time
+type IsiSnapshotUpdateReq struct {
+	Name    string `json:"name,omitempty"`
+	Expires *int64 `json:"expires,omitempty"`
+}
+
+// Isi PAPI snapshot lock JSON struct. A non-nil Expires of zero clears the
+// lock's expiration time
+type IsiSnapshotLock struct {
+	Comment string `json:"comment,omitempty"`
+	Count   int    `json:"count,omitempty"`
+	Expires *int64 `json:"expires,omitempty"`
+	Id      int64  `json:"id,omitempty"`
+}
+
+type getIsiSnapshotLocksResp struct {
+	Locks  []*IsiSnapshotLock `json:"locks"`
+	Resume string             `json:"resume"`
+}
+
+// Isi PAPI snapshot alias JSON struct
+type IsiSnapshotAlias struct {
+	Id         int64  `json:"id"`
+	Name       string `json:"name"`
+	TargetId   int64  `json:"target_id"`
+	TargetName string `json:"target_name"`
+}
+
+type getIsiSnapshotAliasesResp struct {
+	Aliases []*IsiSnapshotAlias `json:"aliases"`
+	Resume  string              `json:"resume"`
+}
+
+type isiSnapshotAliasReq struct {
+	Name   string `json:"name,omitempty"`
+	Target string `json:"target"`
 }
 
 // Isi PAPI snapshot JSON struct
@@ -153,4 +208,36 @@ type IsiUpdateQuotaReq struct {
 
 type isiQuotaListResp struct {
 	Quotas []IsiQuota `json:"quotas"`
+	Resume string     `json:"resume"`
+}
+
+// Isi PAPI job request JSON struct
+type IsiJobReq struct {
+	Type     string   `json:"type"`
+	Paths    []string `json:"paths,omitempty"`
+	Policy   string   `json:"policy,omitempty"`
+	Priority int      `json:"priority,omitempty"`
+}
+
+// Isi PAPI job JSON struct
+type IsiJob struct {
+	Id           int      `json:"id"`
+	Type         string   `json:"type"`
+	State        string   `json:"state"`
+	Paths        []string `json:"paths"`
+	Policy       string   `json:"policy"`
+	Priority     int      `json:"priority"`
+	Progress     string   `json:"progress"`
+	StartTime    int64    `json:"start_time"`
+	EndTime      int64    `json:"end_time"`
+	CurrentPhase int      `json:"current_phase"`
+	TotalPhases  int      `json:"total_phases"`
+}
+
+type postIsiJobResp struct {
+	Id int `json:"id"`
+}
+
+type getIsiJobsResp struct {
+	Jobs []*IsiJob `json:"jobs"`
 }
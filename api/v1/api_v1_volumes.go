@@ -13,6 +13,9 @@ var (
 	recursiveTrueQS = api.OrderedValues{
 		{[]byte("recursive"), []byte("true")},
 	}
+	mergeByteArr        = []byte("merge")
+	continueByteArr     = []byte("continue")
+	trueByteArr         = []byte("true")
 	createVolumeHeaders = map[string]string{
 		"x-isi-ifs-target-type":    "container",
 		"x-isi-ifs-access-control": "public_read_write",
@@ -141,3 +144,40 @@ func CopyIsiVolume(
 		&resp)
 	return resp, err
 }
+
+// CopyIsiVolumeWithOptions copies a directory, specified by its absolute path
+// on the cluster, to a new or existing volume on the cluster. When merge is
+// true the directory's contents are merged into an existing destination, and
+// when cont is true the copy continues after individual files fail to copy.
+func CopyIsiVolumeWithOptions(
+	ctx context.Context,
+	client api.Client,
+	sourcePath, destinationName string,
+	merge, cont bool) (resp *IsiCopyResp, err error) {
+	// PAPI calls: PUT https://1.2.3.4:8080/namespace/path/to/volumes/destination_volume_name?merge=true&continue=true
+	//             x-isi-ifs-copy-source: /namespace/path/to/source
+
+	var params api.OrderedValues
+	if merge {
+		params = append(params, [][]byte{mergeByteArr, trueByteArr})
+	}
+	if cont {
+		params = append(params, [][]byte{continueByteArr, trueByteArr})
+	}
+
+	// copy the directory
+	err = client.Put(
+		ctx,
+		realNamespacePath(client),
+		destinationName,
+		params,
+		map[string]string{
+			"x-isi-ifs-copy-source": path.Join(
+				"/",
+				namespacePath,
+				sourcePath),
+		},
+		nil,
+		&resp)
+	return resp, err
+}
@@ -10,11 +10,20 @@ import (
 )
 
 const (
-	namespacePath       = "namespace"
-	exportsPath         = "platform/2/protocols/nfs/exports"
-	quotaPath           = "platform/2/quota/quotas"
-	snapshotsPath       = "platform/2/snapshot/snapshots"
-	volumeSnapshotsPath = "/ifs/.snapshot"
+	namespacePath                 = "namespace"
+	ifsPath                       = "/ifs"
+	exportsPath                   = "platform/2/protocols/nfs/exports"
+	exportsCheckPath              = "platform/2/protocols/nfs/check"
+	nfsSettingsPath               = "platform/2/protocols/nfs/settings"
+	aliasesPath                   = "platform/2/protocols/nfs/aliases"
+	sharesPath                    = "platform/1/protocols/smb/shares"
+	s3BucketsPath                 = "platform/10/protocols/s3/buckets"
+	s3KeysPath                    = "platform/10/protocols/s3/keys"
+	quotaPath                     = "platform/2/quota/quotas"
+	quotaDefaultNotificationsPath = "platform/2/quota/settings/notifications"
+	snapshotsPath                 = "platform/2/snapshot/snapshots"
+	snapshotSchedulesPath         = "platform/1/snapshot/schedules"
+	volumeSnapshotsPath           = "/ifs/.snapshot"
 )
 
 var (
@@ -30,7 +39,10 @@ func realExportsPath(c api.Client) string {
 	return path.Join(exportsPath, c.VolumesPath())
 }
 
+// realVolumeSnapshotPath returns the namespace path of the client's volumes
+// path inside of the snapshot with the provided name.
 func realVolumeSnapshotPath(c api.Client, name string) string {
-	parts := strings.SplitN(realNamespacePath(c), "/ifs/", 2)
-	return path.Join(parts[0], volumeSnapshotsPath, name, parts[1])
+	return path.Join(
+		namespacePath, volumeSnapshotsPath, name,
+		strings.TrimPrefix(c.VolumesPath(), ifsPath))
 }
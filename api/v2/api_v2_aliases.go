@@ -0,0 +1,266 @@
+package v2
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"strings"
+
+	"github.com/thecodeteam/goisilon/api"
+	"github.com/thecodeteam/goisilon/api/json"
+)
+
+// Alias is an Isilon NFS alias. An alias's name is the short path, beginning
+// with a slash, that clients may mount in place of the alias's path.
+type Alias struct {
+	ID     string       `json:"id,omitmarshal"`
+	Name   *string      `json:"name,omitempty"`
+	Path   *string      `json:"path,omitempty"`
+	Zone   *string      `json:"zone,omitempty"`
+	Health *AliasHealth `json:"health,omitmarshal"`
+}
+
+// AliasList is a list of Isilon NFS aliases.
+type AliasList []*Alias
+
+// MarshalJSON marshals an AliasList to JSON.
+func (l AliasList) MarshalJSON() ([]byte, error) {
+	aliases := struct {
+		Aliases []*Alias `json:"aliases,omitempty"`
+	}{l}
+	return json.Marshal(aliases)
+}
+
+// UnmarshalJSON unmarshals an AliasList from JSON.
+func (l *AliasList) UnmarshalJSON(text []byte) error {
+	aliases := struct {
+		Aliases []*Alias `json:"aliases,omitempty"`
+	}{}
+	if err := json.Unmarshal(text, &aliases); err != nil {
+		return err
+	}
+	*l = aliases.Aliases
+	return nil
+}
+
+type resumeableAliasList struct {
+	Aliases []*Alias `json:"aliases,omitempty"`
+	Resume  string   `json:"resume,omitempty"`
+}
+
+// AliasHealth is the health of an NFS alias's path. The health is only
+// returned when the aliases are inspected with check enabled.
+type AliasHealth uint8
+
+const (
+	// AliasHealthUnknown is an unknown AliasHealth.
+	AliasHealthUnknown AliasHealth = iota
+
+	// AliasHealthGood indicates the alias's path is a valid directory.
+	AliasHealthGood
+
+	// AliasHealthPathNotFound indicates the alias's path does not exist.
+	AliasHealthPathNotFound
+
+	// AliasHealthPathNotDirectory indicates the alias's path is not a
+	// directory.
+	AliasHealthPathNotDirectory
+
+	// AliasHealthPathNotAbsolute indicates the alias's path is not absolute.
+	AliasHealthPathNotAbsolute
+
+	// AliasHealthPathNotValid indicates the alias's path is not valid.
+	AliasHealthPathNotValid
+
+	aliasHealthCount
+)
+
+const (
+	aliasHealthUnknownStr          = "unknown"
+	aliasHealthGoodStr             = "good"
+	aliasHealthPathNotFoundStr     = "path not found"
+	aliasHealthPathNotDirectoryStr = "path not directory"
+	aliasHealthPathNotAbsoluteStr  = "path not absolute"
+	aliasHealthPathNotValidStr     = "path not valid"
+)
+
+var aliasHealthsToStrs = [aliasHealthCount]string{
+	aliasHealthUnknownStr,
+	aliasHealthGoodStr,
+	aliasHealthPathNotFoundStr,
+	aliasHealthPathNotDirectoryStr,
+	aliasHealthPathNotAbsoluteStr,
+	aliasHealthPathNotValidStr,
+}
+
+// ParseAliasHealth parses an AliasHealth from a string.
+func ParseAliasHealth(text string) AliasHealth {
+	for i := AliasHealthGood; i < aliasHealthCount; i++ {
+		if strings.EqualFold(text, aliasHealthsToStrs[i]) {
+			return i
+		}
+	}
+	return AliasHealthUnknown
+}
+
+// String returns the string representation of an AliasHealth value.
+func (h AliasHealth) String() string {
+	if h < (AliasHealthUnknown+1) || h >= aliasHealthCount {
+		return aliasHealthsToStrs[AliasHealthUnknown]
+	}
+	return aliasHealthsToStrs[h]
+}
+
+// MarshalJSON marshals an AliasHealth value to JSON.
+func (h AliasHealth) MarshalJSON() ([]byte, error) {
+	return json.Marshal(h.String())
+}
+
+// UnmarshalJSON unmarshals an AliasHealth value from JSON.
+func (h *AliasHealth) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	*h = ParseAliasHealth(s)
+	return nil
+}
+
+var checkByteArr = []byte("check")
+
+func aliasCheckParams(check bool) api.OrderedValues {
+	if !check {
+		return nil
+	}
+	return api.OrderedValues{{checkByteArr, trueByteArr}}
+}
+
+// aliasID escapes an alias's name so that it may be used as the ID in a
+// request's path. Alias names begin with a slash and may contain others.
+func aliasID(name string) string {
+	return url.PathEscape(name)
+}
+
+// AliasesList GETs all NFS aliases. If check is true then the health of each
+// alias is returned as well.
+func AliasesList(
+	ctx context.Context,
+	client api.Client,
+	check bool) ([]*Alias, error) {
+
+	var (
+		aliases []*Alias
+		params  = aliasCheckParams(check)
+	)
+
+	for {
+		var resp resumeableAliasList
+
+		if err := client.Get(
+			ctx,
+			aliasesPath,
+			"",
+			params,
+			nil,
+			&resp); err != nil {
+
+			return nil, err
+		}
+
+		aliases = append(aliases, resp.Aliases...)
+
+		if resp.Resume == "" {
+			return aliases, nil
+		}
+		params = api.OrderedValues{{resumeByteArr, []byte(resp.Resume)}}
+	}
+}
+
+// AliasInspect GETs an NFS alias. If check is true then the health of the
+// alias is returned as well.
+func AliasInspect(
+	ctx context.Context,
+	client api.Client,
+	name string,
+	check bool) (*Alias, error) {
+
+	var resp AliasList
+
+	if err := client.Get(
+		ctx,
+		aliasesPath,
+		aliasID(name),
+		aliasCheckParams(check),
+		nil,
+		&resp); err != nil {
+
+		return nil, err
+	}
+
+	if len(resp) == 0 {
+		return nil, nil
+	}
+
+	return resp[0], nil
+}
+
+// AliasCreate POSTs an Alias object to the Isilon server.
+func AliasCreate(
+	ctx context.Context,
+	client api.Client,
+	alias *Alias) (string, error) {
+
+	if alias.Name == nil || *alias.Name == "" {
+		return "", errors.New("no name set")
+	}
+	if alias.Path == nil || *alias.Path == "" {
+		return "", errors.New("no path set")
+	}
+
+	var resp Alias
+
+	if err := client.Post(
+		ctx,
+		aliasesPath,
+		"",
+		nil,
+		nil,
+		alias,
+		&resp); err != nil {
+
+		return "", err
+	}
+
+	return resp.ID, nil
+}
+
+// AliasUpdate PUTs an Alias object to the Isilon server.
+func AliasUpdate(
+	ctx context.Context,
+	client api.Client,
+	alias *Alias) error {
+
+	return client.Put(
+		ctx,
+		aliasesPath,
+		aliasID(alias.ID),
+		nil,
+		nil,
+		alias,
+		nil)
+}
+
+// AliasDelete DELETEs an Alias object on the Isilon server.
+func AliasDelete(
+	ctx context.Context,
+	client api.Client,
+	name string) error {
+
+	return client.Delete(
+		ctx,
+		aliasesPath,
+		aliasID(name),
+		nil,
+		nil,
+		nil)
+}
@@ -0,0 +1,44 @@
+package v2
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/thecodeteam/goisilon/api/json"
+)
+
+func TestAliasEncodeJSON(t *testing.T) {
+	var (
+		name   = "/app1"
+		path   = "/ifs/volumes/app1"
+		health = AliasHealthGood
+	)
+	buf, err := json.Marshal(&Alias{
+		ID: name, Name: &name, Path: &path, Health: &health})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, `{"name":"/app1","path":"/ifs/volumes/app1"}`, string(buf))
+}
+
+func TestAliasDecodeJSON(t *testing.T) {
+	j := `{"aliases":[` +
+		`{"health":"path not found","id":"/app1","name":"/app1",` +
+		`"path":"/ifs/volumes/app1","zone":"System"}]}`
+	var l AliasList
+	if err := json.Unmarshal([]byte(j), &l); err != nil {
+		t.Fatal(err)
+	}
+	if !assert.Len(t, l, 1) {
+		t.FailNow()
+	}
+	assert.Equal(t, "/app1", l[0].ID)
+	assert.Equal(t, "System", *l[0].Zone)
+	assert.Equal(t, AliasHealthPathNotFound, *l[0].Health)
+	assert.Equal(t, "path not found", l[0].Health.String())
+}
+
+func TestAliasID(t *testing.T) {
+	assert.Equal(t, "%2Fteam-a%2Fapp1", aliasID("/team-a/app1"))
+}
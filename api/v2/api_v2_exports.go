@@ -3,6 +3,7 @@ package v2
 import (
 	"errors"
 	"strconv"
+	"strings"
 
 	"context"
 
@@ -12,14 +13,167 @@ import (
 
 // Export is an Isilon Export.
 type Export struct {
-	ID          int          `json:"id,omitmarshal"`
-	Paths       *[]string    `json:"paths,omitempty"`
-	Clients     *[]string    `json:"clients,omitempty"`
-	RootClients *[]string    `json:"root_clients,omitempty"`
-	MapAll      *UserMapping `json:"map_all,omitempty"`
-	MapRoot     *UserMapping `json:"map_root,omitempty"`
-	MapNonRoot  *UserMapping `json:"map_non_root,omitempty"`
-	MapFailure  *UserMapping `json:"map_failure,omitempty"`
+	ID                    int               `json:"id,omitmarshal"`
+	Paths                 *[]string         `json:"paths,omitempty"`
+	Clients               *[]string         `json:"clients,omitempty"`
+	RootClients           *[]string         `json:"root_clients,omitempty"`
+	ReadOnlyClients       *[]string         `json:"read_only_clients,omitempty"`
+	ReadWriteClients      *[]string         `json:"read_write_clients,omitempty"`
+	UnresolvedClients     *[]string         `json:"unresolved_clients,omitmarshal"`
+	ConflictingPaths      *[]string         `json:"conflicting_paths,omitmarshal"`
+	MapAll                *UserMapping      `json:"map_all,omitempty"`
+	MapRoot               *UserMapping      `json:"map_root,omitempty"`
+	MapNonRoot            *UserMapping      `json:"map_non_root,omitempty"`
+	MapFailure            *UserMapping      `json:"map_failure,omitempty"`
+	MapFull               *bool             `json:"map_full,omitempty"`
+	MapLookupUID          *bool             `json:"map_lookup_uid,omitempty"`
+	MapRetry              *bool             `json:"map_retry,omitempty"`
+	ReadOnly              *bool             `json:"read_only,omitempty"`
+	SecurityFlavors       *[]SecurityFlavor `json:"security_flavors,omitempty"`
+	Description           *string           `json:"description,omitempty"`
+	AllDirs               *bool             `json:"all_dirs,omitempty"`
+	Zone                  *string           `json:"zone,omitempty"`
+	Snapshot              *string           `json:"snapshot,omitempty"`
+	Encoding              *string           `json:"encoding,omitempty"`
+	BlockSize             *int              `json:"block_size,omitempty"`
+	DirectoryTransferSize *int              `json:"directory_transfer_size,omitempty"`
+	ReadTransferMaxSize   *int              `json:"read_transfer_max_size,omitempty"`
+	ReadTransferMultiple  *int              `json:"read_transfer_multiple,omitempty"`
+	ReadTransferSize      *int              `json:"read_transfer_size,omitempty"`
+	WriteTransferMaxSize  *int              `json:"write_transfer_max_size,omitempty"`
+	WriteTransferMultiple *int              `json:"write_transfer_multiple,omitempty"`
+	WriteTransferSize     *int              `json:"write_transfer_size,omitempty"`
+	MaxFileSize           *int64            `json:"max_file_size,omitempty"`
+	NameMaxSize           *int              `json:"name_max_size,omitempty"`
+	LinkMax               *int              `json:"link_max,omitempty"`
+	CommitAsynchronous    *bool             `json:"commit_asynchronous,omitempty"`
+	SetattrAsynchronous   *bool             `json:"setattr_asynchronous,omitempty"`
+	CanSetTime            *bool             `json:"can_set_time,omitempty"`
+	CaseInsensitive       *bool             `json:"case_insensitive,omitempty"`
+	CasePreserving        *bool             `json:"case_preserving,omitempty"`
+	ChownRestricted       *bool             `json:"chown_restricted,omitempty"`
+	NoTruncate            *bool             `json:"no_truncate,omitempty"`
+	Readdirplus           *bool             `json:"readdirplus,omitempty"`
+	ReaddirplusPrefetch   *int              `json:"readdirplus_prefetch,omitempty"`
+	Return32BitFileIDs    *bool             `json:"return_32bit_file_ids,omitempty"`
+	Symlinks              *bool             `json:"symlinks,omitempty"`
+	TimeDelta             *float64          `json:"time_delta,omitempty"`
+	WriteDatasyncAction   *string           `json:"write_datasync_action,omitempty"`
+	WriteDatasyncReply    *string           `json:"write_datasync_reply,omitempty"`
+	WriteFilesyncAction   *string           `json:"write_filesync_action,omitempty"`
+	WriteFilesyncReply    *string           `json:"write_filesync_reply,omitempty"`
+	WriteUnstableAction   *string           `json:"write_unstable_action,omitempty"`
+	WriteUnstableReply    *string           `json:"write_unstable_reply,omitempty"`
+}
+
+// SecurityFlavor is a valid NFS export security flavor.
+type SecurityFlavor uint8
+
+const (
+	// SecurityFlavorUnknown is an unknown security flavor.
+	SecurityFlavorUnknown SecurityFlavor = iota
+
+	// SecurityFlavorUnix is the AUTH_SYS security flavor.
+	SecurityFlavorUnix
+
+	// SecurityFlavorKerberos5 is the Kerberos V5 security flavor.
+	SecurityFlavorKerberos5
+
+	// SecurityFlavorKerberos5Integrity is the Kerberos V5 security flavor
+	// with integrity.
+	SecurityFlavorKerberos5Integrity
+
+	// SecurityFlavorKerberos5Privacy is the Kerberos V5 security flavor with
+	// privacy.
+	SecurityFlavorKerberos5Privacy
+
+	securityFlavorCount
+)
+
+const (
+	securityFlavorUnknownStr            = "unknown"
+	securityFlavorUnixStr               = "unix"
+	securityFlavorKerberos5Str          = "krb5"
+	securityFlavorKerberos5IntegrityStr = "krb5i"
+	securityFlavorKerberos5PrivacyStr   = "krb5p"
+)
+
+var securityFlavorsToStrs = [securityFlavorCount]string{
+	securityFlavorUnknownStr,
+	securityFlavorUnixStr,
+	securityFlavorKerberos5Str,
+	securityFlavorKerberos5IntegrityStr,
+	securityFlavorKerberos5PrivacyStr,
+}
+
+// ParseSecurityFlavor parses a SecurityFlavor from a string.
+func ParseSecurityFlavor(text string) SecurityFlavor {
+	switch {
+	case strings.EqualFold(text, securityFlavorUnixStr):
+		return SecurityFlavorUnix
+	case strings.EqualFold(text, securityFlavorKerberos5Str):
+		return SecurityFlavorKerberos5
+	case strings.EqualFold(text, securityFlavorKerberos5IntegrityStr):
+		return SecurityFlavorKerberos5Integrity
+	case strings.EqualFold(text, securityFlavorKerberos5PrivacyStr):
+		return SecurityFlavorKerberos5Privacy
+	}
+	return SecurityFlavorUnknown
+}
+
+// String returns the string representation of a SecurityFlavor value.
+func (p SecurityFlavor) String() string {
+	if p < (SecurityFlavorUnknown+1) || p >= securityFlavorCount {
+		return securityFlavorsToStrs[SecurityFlavorUnknown]
+	}
+	return securityFlavorsToStrs[p]
+}
+
+// MarshalJSON marshals a SecurityFlavor value to JSON.
+func (p SecurityFlavor) MarshalJSON() ([]byte, error) {
+	return json.Marshal(p.String())
+}
+
+// UnmarshalJSON unmarshals a SecurityFlavor value from JSON.
+func (p *SecurityFlavor) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	*p = ParseSecurityFlavor(s)
+	return nil
+}
+
+// knownSecurityFlavors returns the provided security flavors without the
+// flavors that are unknown to this package, which cannot be sent back to the
+// cluster. Nil is returned if none of the flavors are known.
+func knownSecurityFlavors(flavors *[]SecurityFlavor) *[]SecurityFlavor {
+	if flavors == nil {
+		return nil
+	}
+	known := make([]SecurityFlavor, 0, len(*flavors))
+	for _, f := range *flavors {
+		if f != SecurityFlavorUnknown {
+			known = append(known, f)
+		}
+	}
+	if len(known) == len(*flavors) {
+		return flavors
+	}
+	if len(known) == 0 {
+		return nil
+	}
+	return &known
+}
+
+// MarshalJSON marshals an Export to JSON. Security flavors that are unknown
+// to this package are left out so that an export read from the cluster may
+// be written back to it.
+func (e Export) MarshalJSON() ([]byte, error) {
+	type export Export
+	ex := export(e)
+	ex.SecurityFlavors = knownSecurityFlavors(e.SecurityFlavors)
+	return json.Marshal(ex)
 }
 
 // ExportList is a list of Isilon Exports.
@@ -45,25 +199,60 @@ func (l *ExportList) UnmarshalJSON(text []byte) error {
 	return nil
 }
 
-// ExportList GETs all exports.
+type resumeableExportList struct {
+	Exports []*Export `json:"exports,omitempty"`
+	Resume  string    `json:"resume,omitempty"`
+}
+
+var pathByteArr = []byte("path")
+
+// ExportsList GETs all exports.
 func ExportsList(
 	ctx context.Context,
 	client api.Client) ([]*Export, error) {
 
-	var resp ExportList
+	return exportsList(ctx, client, nil)
+}
 
-	if err := client.Get(
-		ctx,
-		exportsPath,
-		"",
-		nil,
-		nil,
-		&resp); err != nil {
+// ExportsListByPath GETs the exports that include the provided path. The
+// exports are filtered by the server.
+func ExportsListByPath(
+	ctx context.Context,
+	client api.Client,
+	path string) ([]*Export, error) {
 
-		return nil, err
-	}
+	return exportsList(
+		ctx, client, api.OrderedValues{{pathByteArr, []byte(path)}})
+}
+
+func exportsList(
+	ctx context.Context,
+	client api.Client,
+	params api.OrderedValues) ([]*Export, error) {
+
+	var exports []*Export
+
+	for {
+		var resp resumeableExportList
+
+		if err := client.Get(
+			ctx,
+			exportsPath,
+			"",
+			params,
+			nil,
+			&resp); err != nil {
+
+			return nil, err
+		}
+
+		exports = append(exports, resp.Exports...)
 
-	return resp, nil
+		if resp.Resume == "" {
+			return exports, nil
+		}
+		params = api.OrderedValues{{resumeByteArr, []byte(resp.Resume)}}
+	}
 }
 
 // ExportInspect GETs an export.
@@ -178,3 +367,33 @@ func Unexport(
 
 	return ExportDelete(ctx, client, id)
 }
+
+// ExportCheck is a problem with an existing export reported by the cluster's
+// NFS export check.
+type ExportCheck struct {
+	ID      int    `json:"id,omitempty"`
+	Message string `json:"message,omitempty"`
+}
+
+// ExportsCheck GETs the problems the cluster reports for its exports.
+func ExportsCheck(
+	ctx context.Context,
+	client api.Client) ([]*ExportCheck, error) {
+
+	var resp struct {
+		Checks []*ExportCheck `json:"checks,omitempty"`
+	}
+
+	if err := client.Get(
+		ctx,
+		exportsCheckPath,
+		"",
+		nil,
+		nil,
+		&resp); err != nil {
+
+		return nil, err
+	}
+
+	return resp.Checks, nil
+}
@@ -1,6 +1,7 @@
 package v2
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"testing"
@@ -32,6 +33,81 @@ func TestExportDecodeJSON(t *testing.T) {
 	fmt.Fprintf(os.Stdout, "%+v\n", ex)
 }
 
+func TestExportFullDecodeJSON(t *testing.T) {
+	var exList ExportList
+	if err := json.Unmarshal(getOneExportJSON, &exList); err != nil {
+		t.Fatal(err)
+	}
+	if !assert.Len(t, exList, 1) {
+		t.FailNow()
+	}
+	ex := exList[0]
+
+	assert.Equal(t, 24, ex.ID)
+	assert.False(t, *ex.ReadOnly)
+	assert.False(t, *ex.AllDirs)
+	assert.False(t, *ex.MapLookupUID)
+	assert.False(t, *ex.CommitAsynchronous)
+	assert.Equal(t, "System", *ex.Zone)
+	assert.Equal(t, "", *ex.Description)
+	assert.Equal(t, 8192, *ex.BlockSize)
+	assert.Equal(t, []SecurityFlavor{SecurityFlavorUnix}, *ex.SecurityFlavors)
+	assert.Empty(t, *ex.ReadOnlyClients)
+	assert.Empty(t, *ex.ReadWriteClients)
+	assert.NotNil(t, ex.ConflictingPaths)
+	assert.NotNil(t, ex.UnresolvedClients)
+
+	buf, err := json.Marshal(ex)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := map[string]interface{}{}
+	if err := json.Unmarshal(buf, &m); err != nil {
+		t.Fatal(err)
+	}
+	assert.NotContains(t, m, "id")
+	assert.NotContains(t, m, "conflicting_paths")
+	assert.NotContains(t, m, "unresolved_clients")
+	assert.Equal(t, []interface{}{"unix"}, m["security_flavors"])
+}
+
+func TestSecurityFlavorMarshal(t *testing.T) {
+	assert.Equal(t, "krb5p", SecurityFlavorKerberos5Privacy.String())
+	assert.Equal(
+		t, SecurityFlavorKerberos5Integrity, ParseSecurityFlavor("KRB5I"))
+	assert.Equal(t, SecurityFlavorUnknown, ParseSecurityFlavor("none"))
+
+	buf, err := json.Marshal(SecurityFlavorKerberos5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, `"krb5"`, string(buf))
+}
+
+func TestExportEncodeJSONUnknownSecurityFlavor(t *testing.T) {
+	var ex Export
+	assert.NoError(t, json.Unmarshal(
+		[]byte(`{"id":1,"security_flavors":["unix","krb5x"]}`), &ex))
+	assert.Equal(t,
+		[]SecurityFlavor{SecurityFlavorUnix, SecurityFlavorUnknown},
+		*ex.SecurityFlavors)
+
+	buf, err := json.Marshal(&ex)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"security_flavors":["unix"]}`, string(buf))
+
+	ex.SecurityFlavors = &[]SecurityFlavor{SecurityFlavorUnknown}
+	buf, err = json.Marshal(ex)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{}`, string(buf))
+
+	settings := &NFSExportSettings{
+		SecurityFlavors: &[]SecurityFlavor{SecurityFlavorUnknown}}
+	buf, err = json.Marshal(settings)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{}`, string(buf))
+}
+
 func TestPersonaIDTypeMarshal(t *testing.T) {
 	pidt := PersonaIDTypeUser
 	assert.Equal(t, "user", pidt.String())
@@ -125,3 +201,25 @@ func testAllExportListMarshal(t *testing.T, list []byte) {
 
 	assert.EqualValues(t, map1, map2)
 }
+
+func TestExportsListByPath(t *testing.T) {
+	c := &pagedClient{pages: []string{
+		`{"exports":[{"id":1,"paths":["/ifs/volumes/a"]}],"resume":"r1"}`,
+		`{"exports":[{"id":2,"paths":["/ifs/volumes/a","/ifs/b"]}]}`,
+	}}
+	exports, err := ExportsListByPath(
+		context.Background(), c, "/ifs/volumes/a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !assert.Len(t, exports, 2) {
+		t.FailNow()
+	}
+	assert.Equal(t, 1, exports[0].ID)
+	assert.Equal(t, 2, exports[1].ID)
+	if !assert.Len(t, c.params, 2) {
+		t.FailNow()
+	}
+	assert.Equal(t, "/ifs/volumes/a", c.params[0].StringGet("path"))
+	assert.Equal(t, "r1", c.params[1].StringGet("resume"))
+}
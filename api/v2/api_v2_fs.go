@@ -6,7 +6,6 @@ import (
 	"io"
 	"path"
 	"strings"
-	"sync"
 
 	"github.com/thecodeteam/goisilon/api"
 	"context"
@@ -21,6 +20,7 @@ type ContainerChild struct {
 	Group *string   `json:"group,omitempty"`
 	Mode  *FileMode `json:"mode,omitempty"`
 	Size  *int      `json:"size,omitempty"`
+	MTime *int64    `json:"mtime_val,omitempty"`
 }
 
 type resumeableContainerChildList struct {
@@ -80,6 +80,17 @@ var containerChildrenGetAllDetail = []string{
 	"name",
 }
 
+// ContainerChildrenSyncDetail is the list of detail attributes required to
+// compare a container's children with files on a local file system.
+var ContainerChildrenSyncDetail = []string{
+	"type",
+	"container_path",
+	"size",
+	"mode",
+	"name",
+	"mtime_val",
+}
+
 var containerQueryAll = &ContainerQuery{
 	Result: containerChildrenGetAllDetail,
 }
@@ -123,7 +134,9 @@ func to2DByteArray(sa []string) [][]byte {
 }
 
 // ContainerChildrenGetQuery queries a container for children regardless of
-// ACLs preventing traversal.
+// ACLs preventing traversal. The children channel is closed once all of the
+// children have been received or an error occurs, after which the error, if
+// any, may be read from the error channel.
 func ContainerChildrenGetQuery(
 	ctx context.Context,
 	client api.Client,
@@ -132,12 +145,38 @@ func ContainerChildrenGetQuery(
 	objectType, sortDir string,
 	sort, detail []string) (<-chan *ContainerChild, <-chan error) {
 
+	return containerChildrenGetQuery(
+		ctx, client, realNamespacePath(client), containerPath,
+		limit, maxDepth, objectType, sortDir, sort, detail)
+}
+
+// SnapshotChildrenGetQuery queries a container inside of a snapshot for
+// children regardless of ACLs preventing traversal.
+func SnapshotChildrenGetQuery(
+	ctx context.Context,
+	client api.Client,
+	snapshotName, containerPath string,
+	limit, maxDepth int,
+	objectType, sortDir string,
+	sort, detail []string) (<-chan *ContainerChild, <-chan error) {
+
+	return containerChildrenGetQuery(
+		ctx, client, realVolumeSnapshotPath(client, snapshotName),
+		containerPath, limit, maxDepth, objectType, sortDir, sort, detail)
+}
+
+func containerChildrenGetQuery(
+	ctx context.Context,
+	client api.Client,
+	rnp, containerPath string,
+	limit, maxDepth int,
+	objectType, sortDir string,
+	sort, detail []string) (<-chan *ContainerChild, <-chan error) {
+
 	var (
-		ec  = make(chan error)
-		cc  = make(chan *ContainerChild)
-		wg  = &sync.WaitGroup{}
-		rnp = realNamespacePath(client)
-		qs  = api.OrderedValues{
+		ec = make(chan error, 1)
+		cc = make(chan *ContainerChild)
+		qs = api.OrderedValues{
 			{queryByteArr},
 			{limitByteArr, []byte(fmt.Sprintf("%d", limit))},
 			{maxDepthByteArr, []byte(fmt.Sprintf("%d", maxDepth))},
@@ -159,7 +198,11 @@ func ContainerChildrenGetQuery(
 		qs = append(qs, append(detailQS, to2DByteArray(detail)...))
 	}
 
+	// the error channel is buffered so that the error may always be sent,
+	// even if the receiver has stopped reading the children channel
 	go func() {
+		defer close(ec)
+		defer close(cc)
 		for {
 			var resp resumeableContainerChildList
 			if err := client.Get(
@@ -170,25 +213,21 @@ func ContainerChildrenGetQuery(
 				nil,
 				&resp); err != nil {
 				ec <- err
-				close(ec)
-				close(cc)
 				return
 			}
-			wg.Add(1)
-			go func(resp *resumeableContainerChildList) {
-				defer wg.Done()
-				for _, c := range resp.Children {
-					cc <- c
+			for _, c := range resp.Children {
+				select {
+				case cc <- c:
+				case <-ctx.Done():
+					ec <- ctx.Err()
+					return
 				}
-			}(&resp)
+			}
 			if resp.Resume == "" {
-				break
+				return
 			}
 			qs.Set(resumeByteArr, []byte(resp.Resume))
 		}
-		wg.Wait()
-		close(ec)
-		close(cc)
 	}()
 	return cc, ec
 }
@@ -205,25 +244,13 @@ func ContainerChildrenGetAll(
 		ctx, client, containerPath,
 		2, -1, "", "", nil, containerChildrenGetAllDetail)
 
-	done := make(chan int)
-
-	go func() {
-		defer close(done)
-		for c := range cc {
-			children = append(children, c)
-		}
-	}()
-
-	for {
-		select {
-		case <-done:
-			return children, nil
-		case e := <-ec:
-			if e != nil {
-				return nil, e
-			}
-		}
+	for c := range cc {
+		children = append(children, c)
+	}
+	if err := <-ec; err != nil {
+		return nil, err
 	}
+	return children, nil
 }
 
 // ContainerChildrenMapAll GETs all descendent children of a container and
@@ -358,6 +385,61 @@ func ContainerCreateFile(
 		nil)
 }
 
+// ContainerGetFile GETs the contents of a file that is a child object of a
+// container and writes them to w.
+func ContainerGetFile(
+	ctx context.Context,
+	client api.Client,
+	filePath string,
+	w io.Writer) error {
+
+	return client.Get(
+		ctx,
+		realNamespacePath(client),
+		filePath,
+		nil,
+		nil,
+		w)
+}
+
+// SnapshotGetFile GETs the contents of a file inside of a snapshot and
+// writes them to w.
+func SnapshotGetFile(
+	ctx context.Context,
+	client api.Client,
+	snapshotName, filePath string,
+	w io.Writer) error {
+
+	return client.Get(
+		ctx,
+		realVolumeSnapshotPath(client, snapshotName),
+		filePath,
+		nil,
+		nil,
+		w)
+}
+
+// SnapshotPath returns the absolute path to a path beneath /ifs inside of a
+// snapshot. The path of /ifs itself is the root of the snapshot.
+func SnapshotPath(snapshotName, p string) string {
+	return path.Join(
+		volumeSnapshotsPath, snapshotName,
+		strings.TrimPrefix(path.Clean(p), ifsPath))
+}
+
+// VolumeSnapshotPath returns the absolute path to a volume inside of a
+// snapshot.
+func VolumeSnapshotPath(
+	client api.Client,
+	snapshotName, volumeName string) string {
+
+	return path.Join(
+		"/",
+		strings.TrimPrefix(
+			realVolumeSnapshotPath(client, snapshotName), namespacePath),
+		volumeName)
+}
+
 // ContainerChildDelete deletes a child of a container.
 func ContainerChildDelete(
 	ctx context.Context,
@@ -0,0 +1,211 @@
+package v2
+
+import (
+	"context"
+	"path"
+
+	"github.com/thecodeteam/goisilon/api"
+	"github.com/thecodeteam/goisilon/api/json"
+)
+
+// NFSGlobalSettings are the cluster-wide NFS protocol settings.
+type NFSGlobalSettings struct {
+	NFSv3Enabled   *bool `json:"nfsv3_enabled,omitempty"`
+	NFSv4Enabled   *bool `json:"nfsv4_enabled,omitempty"`
+	NFSv40Enabled  *bool `json:"nfsv40_enabled,omitempty"`
+	NFSv41Enabled  *bool `json:"nfsv41_enabled,omitempty"`
+	RPCMaxThreads  *int  `json:"rpc_maxthreads,omitempty"`
+	RPCMinThreads  *int  `json:"rpc_minthreads,omitempty"`
+	RquotaEnabled  *bool `json:"rquota_enabled,omitempty"`
+	Service        *bool `json:"service,omitempty"`
+	NFSRDMAEnabled *bool `json:"nfs_rdma_enabled,omitempty"`
+}
+
+// NFSExportSettings are the default settings applied to the NFS exports of
+// an access zone.
+type NFSExportSettings struct {
+	MapAll                *UserMapping      `json:"map_all,omitempty"`
+	MapRoot               *UserMapping      `json:"map_root,omitempty"`
+	MapNonRoot            *UserMapping      `json:"map_non_root,omitempty"`
+	MapFailure            *UserMapping      `json:"map_failure,omitempty"`
+	MapFull               *bool             `json:"map_full,omitempty"`
+	MapLookupUID          *bool             `json:"map_lookup_uid,omitempty"`
+	MapRetry              *bool             `json:"map_retry,omitempty"`
+	ReadOnly              *bool             `json:"read_only,omitempty"`
+	SecurityFlavors       *[]SecurityFlavor `json:"security_flavors,omitempty"`
+	AllDirs               *bool             `json:"all_dirs,omitempty"`
+	Encoding              *string           `json:"encoding,omitempty"`
+	BlockSize             *int              `json:"block_size,omitempty"`
+	DirectoryTransferSize *int              `json:"directory_transfer_size,omitempty"`
+	ReadTransferMaxSize   *int              `json:"read_transfer_max_size,omitempty"`
+	ReadTransferSize      *int              `json:"read_transfer_size,omitempty"`
+	WriteTransferMaxSize  *int              `json:"write_transfer_max_size,omitempty"`
+	WriteTransferSize     *int              `json:"write_transfer_size,omitempty"`
+	CommitAsynchronous    *bool             `json:"commit_asynchronous,omitempty"`
+	SetattrAsynchronous   *bool             `json:"setattr_asynchronous,omitempty"`
+	CanSetTime            *bool             `json:"can_set_time,omitempty"`
+	ChownRestricted       *bool             `json:"chown_restricted,omitempty"`
+	Return32BitFileIDs    *bool             `json:"return_32bit_file_ids,omitempty"`
+	Symlinks              *bool             `json:"symlinks,omitempty"`
+	TimeDelta             *float64          `json:"time_delta,omitempty"`
+}
+
+// MarshalJSON marshals NFSExportSettings to JSON. Security flavors that are
+// unknown to this package are left out.
+func (s NFSExportSettings) MarshalJSON() ([]byte, error) {
+	type settings NFSExportSettings
+	es := settings(s)
+	es.SecurityFlavors = knownSecurityFlavors(s.SecurityFlavors)
+	return json.Marshal(es)
+}
+
+// NFSZoneSettings are the NFSv4 settings of an access zone.
+type NFSZoneSettings struct {
+	NFSv4Domain          *string `json:"nfsv4_domain,omitempty"`
+	NFSv4AllowNumericIDs *bool   `json:"nfsv4_allow_numeric_ids,omitempty"`
+	NFSv4NoDomain        *bool   `json:"nfsv4_no_domain,omitempty"`
+	NFSv4NoDomainUIDs    *bool   `json:"nfsv4_no_domain_uids,omitempty"`
+	NFSv4NoNames         *bool   `json:"nfsv4_no_names,omitempty"`
+	NFSv4ReplaceDomain   *bool   `json:"nfsv4_replace_domain,omitempty"`
+}
+
+const (
+	nfsSettingsGlobal = "global"
+	nfsSettingsExport = "export"
+	nfsSettingsZone   = "zone"
+)
+
+var zoneByteArr = []byte("zone")
+
+func zoneParams(zone string) api.OrderedValues {
+	if zone == "" {
+		return nil
+	}
+	return api.OrderedValues{{zoneByteArr, []byte(zone)}}
+}
+
+// NFSGlobalSettingsGet GETs the global NFS settings.
+func NFSGlobalSettingsGet(
+	ctx context.Context,
+	client api.Client) (*NFSGlobalSettings, error) {
+
+	var resp struct {
+		Settings *NFSGlobalSettings `json:"settings,omitempty"`
+	}
+
+	if err := client.Get(
+		ctx,
+		path.Join(nfsSettingsPath, nfsSettingsGlobal),
+		"",
+		nil,
+		nil,
+		&resp); err != nil {
+
+		return nil, err
+	}
+
+	return resp.Settings, nil
+}
+
+// NFSGlobalSettingsUpdate PUTs the global NFS settings. Only the settings'
+// non-nil fields are modified.
+func NFSGlobalSettingsUpdate(
+	ctx context.Context,
+	client api.Client,
+	settings *NFSGlobalSettings) error {
+
+	return client.Put(
+		ctx,
+		path.Join(nfsSettingsPath, nfsSettingsGlobal),
+		"",
+		nil,
+		nil,
+		settings,
+		nil)
+}
+
+// NFSExportSettingsGet GETs the default NFS export settings of an access
+// zone. If zone is empty then the settings of the System zone are returned.
+func NFSExportSettingsGet(
+	ctx context.Context,
+	client api.Client,
+	zone string) (*NFSExportSettings, error) {
+
+	var resp struct {
+		Settings *NFSExportSettings `json:"settings,omitempty"`
+	}
+
+	if err := client.Get(
+		ctx,
+		path.Join(nfsSettingsPath, nfsSettingsExport),
+		"",
+		zoneParams(zone),
+		nil,
+		&resp); err != nil {
+
+		return nil, err
+	}
+
+	return resp.Settings, nil
+}
+
+// NFSExportSettingsUpdate PUTs the default NFS export settings of an access
+// zone. Only the settings' non-nil fields are modified.
+func NFSExportSettingsUpdate(
+	ctx context.Context,
+	client api.Client,
+	zone string,
+	settings *NFSExportSettings) error {
+
+	return client.Put(
+		ctx,
+		path.Join(nfsSettingsPath, nfsSettingsExport),
+		"",
+		zoneParams(zone),
+		nil,
+		settings,
+		nil)
+}
+
+// NFSZoneSettingsGet GETs the NFS settings of an access zone. If zone is
+// empty then the settings of the System zone are returned.
+func NFSZoneSettingsGet(
+	ctx context.Context,
+	client api.Client,
+	zone string) (*NFSZoneSettings, error) {
+
+	var resp struct {
+		Settings *NFSZoneSettings `json:"settings,omitempty"`
+	}
+
+	if err := client.Get(
+		ctx,
+		path.Join(nfsSettingsPath, nfsSettingsZone),
+		"",
+		zoneParams(zone),
+		nil,
+		&resp); err != nil {
+
+		return nil, err
+	}
+
+	return resp.Settings, nil
+}
+
+// NFSZoneSettingsUpdate PUTs the NFS settings of an access zone. Only the
+// settings' non-nil fields are modified.
+func NFSZoneSettingsUpdate(
+	ctx context.Context,
+	client api.Client,
+	zone string,
+	settings *NFSZoneSettings) error {
+
+	return client.Put(
+		ctx,
+		path.Join(nfsSettingsPath, nfsSettingsZone),
+		"",
+		zoneParams(zone),
+		nil,
+		settings,
+		nil)
+}
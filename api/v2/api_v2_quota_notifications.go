@@ -0,0 +1,358 @@
+package v2
+
+import (
+	"context"
+	"errors"
+	"path"
+	"strings"
+
+	"github.com/thecodeteam/goisilon/api"
+	"github.com/thecodeteam/goisilon/api/json"
+)
+
+// QuotaNotification is a rule that notifies when a quota's threshold meets
+// a condition. A quota's rules replace the cluster's default rules.
+type QuotaNotification struct {
+	ID                 string                      `json:"id,omitmarshal"`
+	Condition          *QuotaNotificationCondition `json:"condition,omitempty"`
+	Threshold          *QuotaThreshold             `json:"threshold,omitempty"`
+	Schedule           *string                     `json:"schedule,omitempty"`
+	Holdoff            *int                        `json:"holdoff,omitempty"`
+	ActionAlert        *bool                       `json:"action_alert,omitempty"`
+	ActionEmailOwner   *bool                       `json:"action_email_owner,omitempty"`
+	ActionEmailAddress *string                     `json:"action_email_address,omitempty"`
+	EmailTemplate      *string                     `json:"email_template,omitempty"`
+}
+
+// QuotaNotificationList is a list of quota notification rules.
+type QuotaNotificationList []*QuotaNotification
+
+// MarshalJSON marshals a QuotaNotificationList to JSON.
+func (l QuotaNotificationList) MarshalJSON() ([]byte, error) {
+	notifications := struct {
+		Notifications []*QuotaNotification `json:"notifications,omitempty"`
+	}{l}
+	return json.Marshal(notifications)
+}
+
+// UnmarshalJSON unmarshals a QuotaNotificationList from JSON.
+func (l *QuotaNotificationList) UnmarshalJSON(text []byte) error {
+	notifications := struct {
+		Notifications []*QuotaNotification `json:"notifications,omitempty"`
+	}{}
+	if err := json.Unmarshal(text, &notifications); err != nil {
+		return err
+	}
+	*l = notifications.Notifications
+	return nil
+}
+
+// QuotaNotificationCondition is the condition of a quota's threshold that
+// triggers a notification.
+type QuotaNotificationCondition uint8
+
+const (
+	// QuotaNotificationConditionUnknown is an unknown condition.
+	QuotaNotificationConditionUnknown QuotaNotificationCondition = iota
+
+	// QuotaNotificationConditionExceeded is met when the threshold is
+	// exceeded.
+	QuotaNotificationConditionExceeded
+
+	// QuotaNotificationConditionDenied is met when a write is denied
+	// because it would exceed the threshold.
+	QuotaNotificationConditionDenied
+
+	// QuotaNotificationConditionViolatedGrace is met while the soft
+	// threshold remains exceeded after its grace period.
+	QuotaNotificationConditionViolatedGrace
+
+	// QuotaNotificationConditionExpired is met when the grace period of the
+	// soft threshold expires.
+	QuotaNotificationConditionExpired
+
+	quotaNotificationConditionCount
+)
+
+var (
+	// PQuotaNotificationConditionExceeded is used to grab a pointer to a
+	// const.
+	PQuotaNotificationConditionExceeded = QuotaNotificationConditionExceeded
+
+	// PQuotaNotificationConditionDenied is used to grab a pointer to a
+	// const.
+	PQuotaNotificationConditionDenied = QuotaNotificationConditionDenied
+
+	// PQuotaNotificationConditionViolatedGrace is used to grab a pointer to
+	// a const.
+	PQuotaNotificationConditionViolatedGrace = QuotaNotificationConditionViolatedGrace
+
+	// PQuotaNotificationConditionExpired is used to grab a pointer to a
+	// const.
+	PQuotaNotificationConditionExpired = QuotaNotificationConditionExpired
+)
+
+const (
+	quotaNotificationConditionUnknownStr       = "unknown"
+	quotaNotificationConditionExceededStr      = "exceeded"
+	quotaNotificationConditionDeniedStr        = "denied"
+	quotaNotificationConditionViolatedGraceStr = "violated_grace"
+	quotaNotificationConditionExpiredStr       = "expired"
+)
+
+var quotaNotificationConditionsToStrs = [quotaNotificationConditionCount]string{
+	quotaNotificationConditionUnknownStr,
+	quotaNotificationConditionExceededStr,
+	quotaNotificationConditionDeniedStr,
+	quotaNotificationConditionViolatedGraceStr,
+	quotaNotificationConditionExpiredStr,
+}
+
+// ParseQuotaNotificationCondition parses a QuotaNotificationCondition from
+// a string.
+func ParseQuotaNotificationCondition(
+	text string) QuotaNotificationCondition {
+
+	for i := QuotaNotificationConditionExceeded; i < quotaNotificationConditionCount; i++ {
+		if strings.EqualFold(text, quotaNotificationConditionsToStrs[i]) {
+			return i
+		}
+	}
+	return QuotaNotificationConditionUnknown
+}
+
+// String returns the string representation of a QuotaNotificationCondition
+// value.
+func (c QuotaNotificationCondition) String() string {
+	if c < (QuotaNotificationConditionUnknown+1) ||
+		c >= quotaNotificationConditionCount {
+		return quotaNotificationConditionsToStrs[QuotaNotificationConditionUnknown]
+	}
+	return quotaNotificationConditionsToStrs[c]
+}
+
+// MarshalJSON marshals a QuotaNotificationCondition value to JSON.
+func (c QuotaNotificationCondition) MarshalJSON() ([]byte, error) {
+	return json.Marshal(c.String())
+}
+
+// UnmarshalJSON unmarshals a QuotaNotificationCondition value from JSON.
+func (c *QuotaNotificationCondition) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	*c = ParseQuotaNotificationCondition(s)
+	return nil
+}
+
+// QuotaThreshold is one of a quota's thresholds.
+type QuotaThreshold uint8
+
+const (
+	// QuotaThresholdUnknown is an unknown threshold.
+	QuotaThresholdUnknown QuotaThreshold = iota
+
+	// QuotaThresholdAdvisory is the advisory threshold.
+	QuotaThresholdAdvisory
+
+	// QuotaThresholdSoft is the soft threshold.
+	QuotaThresholdSoft
+
+	// QuotaThresholdHard is the hard threshold.
+	QuotaThresholdHard
+
+	quotaThresholdCount
+)
+
+var (
+	// PQuotaThresholdAdvisory is used to grab a pointer to a const.
+	PQuotaThresholdAdvisory = QuotaThresholdAdvisory
+
+	// PQuotaThresholdSoft is used to grab a pointer to a const.
+	PQuotaThresholdSoft = QuotaThresholdSoft
+
+	// PQuotaThresholdHard is used to grab a pointer to a const.
+	PQuotaThresholdHard = QuotaThresholdHard
+)
+
+const (
+	quotaThresholdUnknownStr  = "unknown"
+	quotaThresholdAdvisoryStr = "advisory"
+	quotaThresholdSoftStr     = "soft"
+	quotaThresholdHardStr     = "hard"
+)
+
+var quotaThresholdsToStrs = [quotaThresholdCount]string{
+	quotaThresholdUnknownStr,
+	quotaThresholdAdvisoryStr,
+	quotaThresholdSoftStr,
+	quotaThresholdHardStr,
+}
+
+// ParseQuotaThreshold parses a QuotaThreshold from a string.
+func ParseQuotaThreshold(text string) QuotaThreshold {
+	for i := QuotaThresholdAdvisory; i < quotaThresholdCount; i++ {
+		if strings.EqualFold(text, quotaThresholdsToStrs[i]) {
+			return i
+		}
+	}
+	return QuotaThresholdUnknown
+}
+
+// String returns the string representation of a QuotaThreshold value.
+func (t QuotaThreshold) String() string {
+	if t < (QuotaThresholdUnknown+1) || t >= quotaThresholdCount {
+		return quotaThresholdsToStrs[QuotaThresholdUnknown]
+	}
+	return quotaThresholdsToStrs[t]
+}
+
+// MarshalJSON marshals a QuotaThreshold value to JSON.
+func (t QuotaThreshold) MarshalJSON() ([]byte, error) {
+	return json.Marshal(t.String())
+}
+
+// UnmarshalJSON unmarshals a QuotaThreshold value from JSON.
+func (t *QuotaThreshold) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	*t = ParseQuotaThreshold(s)
+	return nil
+}
+
+// quotaNotificationsPath returns the path of a quota's notification rules,
+// or of the default rules if the quota ID is empty.
+func quotaNotificationsPath(quotaID string) string {
+	if quotaID == "" {
+		return quotaDefaultNotificationsPath
+	}
+	return path.Join(quotaPath, quotaID, "notifications")
+}
+
+// QuotaNotificationsList GETs a quota's notification rules. If the quota ID
+// is empty then the cluster's default rules are returned.
+func QuotaNotificationsList(
+	ctx context.Context,
+	client api.Client,
+	quotaID string) ([]*QuotaNotification, error) {
+
+	var resp QuotaNotificationList
+
+	if err := client.Get(
+		ctx,
+		quotaNotificationsPath(quotaID),
+		"",
+		nil,
+		nil,
+		&resp); err != nil {
+
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// QuotaNotificationInspect GETs a quota's notification rule. If the quota
+// ID is empty then one of the cluster's default rules is returned.
+func QuotaNotificationInspect(
+	ctx context.Context,
+	client api.Client,
+	quotaID, id string) (*QuotaNotification, error) {
+
+	var resp QuotaNotificationList
+
+	if err := client.Get(
+		ctx,
+		quotaNotificationsPath(quotaID),
+		id,
+		nil,
+		nil,
+		&resp); err != nil {
+
+		return nil, err
+	}
+
+	if len(resp) == 0 {
+		return nil, nil
+	}
+
+	return resp[0], nil
+}
+
+// QuotaNotificationCreate POSTs a notification rule for a quota and returns
+// the rule's ID. If the quota ID is empty then the rule is added to the
+// cluster's default rules.
+func QuotaNotificationCreate(
+	ctx context.Context,
+	client api.Client,
+	quotaID string,
+	notification *QuotaNotification) (string, error) {
+
+	if notification.Condition == nil {
+		return "", errors.New("no condition set")
+	}
+	if notification.Threshold == nil {
+		return "", errors.New("no threshold set")
+	}
+
+	var resp QuotaNotification
+
+	if err := client.Post(
+		ctx,
+		quotaNotificationsPath(quotaID),
+		"",
+		nil,
+		nil,
+		notification,
+		&resp); err != nil {
+
+		return "", err
+	}
+
+	return resp.ID, nil
+}
+
+// QuotaNotificationUpdate PUTs a quota's notification rule. A rule's
+// condition and threshold cannot be modified and are not sent. If the quota
+// ID is empty then one of the cluster's default rules is updated.
+func QuotaNotificationUpdate(
+	ctx context.Context,
+	client api.Client,
+	quotaID string,
+	notification *QuotaNotification) error {
+
+	return client.Put(
+		ctx,
+		quotaNotificationsPath(quotaID),
+		notification.ID,
+		nil,
+		nil,
+		&QuotaNotification{
+			Schedule:           notification.Schedule,
+			Holdoff:            notification.Holdoff,
+			ActionAlert:        notification.ActionAlert,
+			ActionEmailOwner:   notification.ActionEmailOwner,
+			ActionEmailAddress: notification.ActionEmailAddress,
+			EmailTemplate:      notification.EmailTemplate,
+		},
+		nil)
+}
+
+// QuotaNotificationDelete DELETEs a quota's notification rule. If the quota
+// ID is empty then one of the cluster's default rules is deleted.
+func QuotaNotificationDelete(
+	ctx context.Context,
+	client api.Client,
+	quotaID, id string) error {
+
+	return client.Delete(
+		ctx,
+		quotaNotificationsPath(quotaID),
+		id,
+		nil,
+		nil,
+		nil)
+}
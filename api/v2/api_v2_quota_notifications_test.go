@@ -0,0 +1,54 @@
+package v2
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/thecodeteam/goisilon/api/json"
+)
+
+func TestQuotaNotificationDecodeJSON(t *testing.T) {
+	var l QuotaNotificationList
+	if err := json.Unmarshal([]byte(`{"notifications":[{
+		"action_alert":true,
+		"action_email_address":"ops@example.com",
+		"action_email_owner":false,
+		"condition":"violated_grace",
+		"holdoff":300,
+		"id":"0123",
+		"schedule":"every 1 days at 09:00",
+		"threshold":"soft"}]}`), &l); err != nil {
+		t.Fatal(err)
+	}
+	if !assert.Len(t, l, 1) {
+		t.FailNow()
+	}
+	n := l[0]
+	assert.Equal(t, "0123", n.ID)
+	assert.Equal(t, QuotaNotificationConditionViolatedGrace, *n.Condition)
+	assert.Equal(t, QuotaThresholdSoft, *n.Threshold)
+	assert.Equal(t, 300, *n.Holdoff)
+	assert.True(t, *n.ActionAlert)
+	assert.Equal(t, "ops@example.com", *n.ActionEmailAddress)
+}
+
+func TestQuotaNotificationEncodeJSON(t *testing.T) {
+	buf, err := json.Marshal(&QuotaNotification{
+		ID:        "0123",
+		Condition: &PQuotaNotificationConditionExceeded,
+		Threshold: &PQuotaThresholdAdvisory,
+	})
+	assert.NoError(t, err)
+	assert.JSONEq(t,
+		`{"condition":"exceeded","threshold":"advisory"}`, string(buf))
+}
+
+func TestQuotaNotificationsPath(t *testing.T) {
+	assert.Equal(t,
+		"platform/2/quota/quotas/q1/notifications",
+		quotaNotificationsPath("q1"))
+	assert.Equal(t,
+		"platform/2/quota/settings/notifications",
+		quotaNotificationsPath(""))
+}
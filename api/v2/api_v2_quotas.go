@@ -0,0 +1,390 @@
+package v2
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/thecodeteam/goisilon/api"
+	"github.com/thecodeteam/goisilon/api/json"
+)
+
+// Quota is an Isilon SmartQuotas quota.
+type Quota struct {
+	ID                        string           `json:"id,omitmarshal"`
+	Path                      *string          `json:"path,omitempty"`
+	Type                      *QuotaType       `json:"type,omitempty"`
+	Persona                   *Persona         `json:"persona,omitempty"`
+	IncludeSnapshots          *bool            `json:"include_snapshots,omitempty"`
+	ThresholdsIncludeOverhead *bool            `json:"thresholds_include_overhead,omitempty"`
+	Container                 *bool            `json:"container,omitempty"`
+	Enforced                  *bool            `json:"enforced,omitempty"`
+	Thresholds                *QuotaThresholds `json:"thresholds,omitempty"`
+	Notifications             *string          `json:"notifications,omitmarshal"`
+	Linked                    *bool            `json:"linked,omitempty"`
+	Ready                     *bool            `json:"ready,omitmarshal"`
+	Usage                     *QuotaUsage      `json:"usage,omitmarshal"`
+}
+
+// QuotaThresholds are a quota's thresholds in bytes. A nil threshold is not
+// set, and is cleared when the thresholds are updated.
+type QuotaThresholds struct {
+	Advisory             *int64 `json:"advisory"`
+	Soft                 *int64 `json:"soft"`
+	Hard                 *int64 `json:"hard"`
+	SoftGrace            *int   `json:"soft_grace,omitempty"`
+	AdvisoryExceeded     *bool  `json:"advisory_exceeded,omitmarshal"`
+	AdvisoryLastExceeded *int64 `json:"advisory_last_exceeded,omitmarshal"`
+	SoftExceeded         *bool  `json:"soft_exceeded,omitmarshal"`
+	SoftLastExceeded     *int64 `json:"soft_last_exceeded,omitmarshal"`
+	HardExceeded         *bool  `json:"hard_exceeded,omitmarshal"`
+	HardLastExceeded     *int64 `json:"hard_last_exceeded,omitmarshal"`
+}
+
+// QuotaUsage is the usage accounted by a quota.
+type QuotaUsage struct {
+	Inodes   *int64 `json:"inodes,omitempty"`
+	Logical  *int64 `json:"logical,omitempty"`
+	Physical *int64 `json:"physical,omitempty"`
+}
+
+// QuotaList is a list of Isilon quotas.
+type QuotaList []*Quota
+
+// MarshalJSON marshals a QuotaList to JSON.
+func (l QuotaList) MarshalJSON() ([]byte, error) {
+	quotas := struct {
+		Quotas []*Quota `json:"quotas,omitempty"`
+	}{l}
+	return json.Marshal(quotas)
+}
+
+// UnmarshalJSON unmarshals a QuotaList from JSON.
+func (l *QuotaList) UnmarshalJSON(text []byte) error {
+	quotas := struct {
+		Quotas []*Quota `json:"quotas,omitempty"`
+	}{}
+	if err := json.Unmarshal(text, &quotas); err != nil {
+		return err
+	}
+	*l = quotas.Quotas
+	return nil
+}
+
+type resumeableQuotaList struct {
+	Quotas []*Quota `json:"quotas,omitempty"`
+	Resume string   `json:"resume,omitempty"`
+}
+
+// QuotaType is a possible value used with a Quota's Type field.
+type QuotaType uint8
+
+const (
+	// QuotaTypeUnknown is an unknown QuotaType.
+	QuotaTypeUnknown QuotaType = iota
+
+	// QuotaTypeDirectory is a quota on all of the data in a directory.
+	QuotaTypeDirectory
+
+	// QuotaTypeUser is a quota on the data a user owns in a directory.
+	QuotaTypeUser
+
+	// QuotaTypeGroup is a quota on the data a group owns in a directory.
+	QuotaTypeGroup
+
+	// QuotaTypeDefaultUser is a template for the user quotas of a directory.
+	QuotaTypeDefaultUser
+
+	// QuotaTypeDefaultGroup is a template for the group quotas of a
+	// directory.
+	QuotaTypeDefaultGroup
+
+	quotaTypeCount
+)
+
+var (
+	// PQuotaTypeDirectory is used to grab a pointer to a const.
+	PQuotaTypeDirectory = QuotaTypeDirectory
+
+	// PQuotaTypeUser is used to grab a pointer to a const.
+	PQuotaTypeUser = QuotaTypeUser
+
+	// PQuotaTypeGroup is used to grab a pointer to a const.
+	PQuotaTypeGroup = QuotaTypeGroup
+
+	// PQuotaTypeDefaultUser is used to grab a pointer to a const.
+	PQuotaTypeDefaultUser = QuotaTypeDefaultUser
+
+	// PQuotaTypeDefaultGroup is used to grab a pointer to a const.
+	PQuotaTypeDefaultGroup = QuotaTypeDefaultGroup
+)
+
+const (
+	quotaTypeUnknownStr      = "unknown"
+	quotaTypeDirectoryStr    = "directory"
+	quotaTypeUserStr         = "user"
+	quotaTypeGroupStr        = "group"
+	quotaTypeDefaultUserStr  = "default-user"
+	quotaTypeDefaultGroupStr = "default-group"
+)
+
+var quotaTypesToStrs = [quotaTypeCount]string{
+	quotaTypeUnknownStr,
+	quotaTypeDirectoryStr,
+	quotaTypeUserStr,
+	quotaTypeGroupStr,
+	quotaTypeDefaultUserStr,
+	quotaTypeDefaultGroupStr,
+}
+
+// ParseQuotaType parses a QuotaType from a string.
+func ParseQuotaType(text string) QuotaType {
+	for i := QuotaTypeDirectory; i < quotaTypeCount; i++ {
+		if strings.EqualFold(text, quotaTypesToStrs[i]) {
+			return i
+		}
+	}
+	return QuotaTypeUnknown
+}
+
+// String returns the string representation of a QuotaType value.
+func (t QuotaType) String() string {
+	if t < (QuotaTypeUnknown+1) || t >= quotaTypeCount {
+		return quotaTypesToStrs[QuotaTypeUnknown]
+	}
+	return quotaTypesToStrs[t]
+}
+
+// MarshalJSON marshals a QuotaType value to JSON.
+func (t QuotaType) MarshalJSON() ([]byte, error) {
+	return json.Marshal(t.String())
+}
+
+// UnmarshalJSON unmarshals a QuotaType value from JSON.
+func (t *QuotaType) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	*t = ParseQuotaType(s)
+	return nil
+}
+
+// QuotaFilter limits the quotas returned by QuotasListWithFilter.
+type QuotaFilter struct {
+
+	// Type, if not nil, only matches quotas of the type.
+	Type *QuotaType
+
+	// Persona, if not nil, only matches the user or group quotas of the
+	// persona. The persona must have an ID, or a type and a name.
+	Persona *Persona
+
+	// Path, if not nil, only matches the quotas of the path.
+	Path *string
+
+	// RecursePathChildren also matches the quotas of the path's
+	// descendants. It is ignored unless Path is set.
+	RecursePathChildren bool
+}
+
+var (
+	personaByteArr             = []byte("persona")
+	recursePathChildrenByteArr = []byte("recurse_path_children")
+)
+
+func (f *QuotaFilter) params() (api.OrderedValues, error) {
+	var params api.OrderedValues
+	if f == nil {
+		return params, nil
+	}
+	if f.Type != nil {
+		params = append(params, [][]byte{typeByteArr, []byte(f.Type.String())})
+	}
+	if f.Persona != nil {
+		p, err := personaQueryValue(f.Persona)
+		if err != nil {
+			return nil, err
+		}
+		params = append(params, [][]byte{personaByteArr, []byte(p)})
+	}
+	if f.Path != nil {
+		params = append(params, [][]byte{pathByteArr, []byte(*f.Path)})
+		if f.RecursePathChildren {
+			params = append(
+				params, [][]byte{recursePathChildrenByteArr, trueByteArr})
+		}
+	}
+	return params, nil
+}
+
+// personaQueryValue returns the representation of a persona used to filter
+// quotas, such as "UID:2000" or "USER:akutz".
+func personaQueryValue(p *Persona) (string, error) {
+	if p.ID != nil {
+		t := p.ID.Type.String()
+		if p.ID.Type == PersonaIDTypeUser || p.ID.Type == PersonaIDTypeGroup {
+			t = strings.ToUpper(t)
+		}
+		return fmt.Sprintf("%s:%s", t, p.ID.ID), nil
+	}
+	if p.Type != nil && p.Name != nil {
+		return fmt.Sprintf(
+			"%s:%s", strings.ToUpper(p.Type.String()), *p.Name), nil
+	}
+	return "", fmt.Errorf("persona cannot be used as a filter: %+v", p)
+}
+
+// QuotasList GETs all quotas.
+func QuotasList(
+	ctx context.Context,
+	client api.Client) ([]*Quota, error) {
+
+	return QuotasListWithFilter(ctx, client, nil)
+}
+
+// QuotasListByPath GETs the quotas of all types for a path.
+func QuotasListByPath(
+	ctx context.Context,
+	client api.Client,
+	path string) ([]*Quota, error) {
+
+	return QuotasListWithFilter(ctx, client, &QuotaFilter{Path: &path})
+}
+
+// QuotasListWithFilter GETs the quotas that match the provided filter. The
+// quotas are filtered by the server.
+func QuotasListWithFilter(
+	ctx context.Context,
+	client api.Client,
+	filter *QuotaFilter) ([]*Quota, error) {
+
+	params, err := filter.params()
+	if err != nil {
+		return nil, err
+	}
+
+	var quotas []*Quota
+
+	for {
+		var resp resumeableQuotaList
+
+		if err := client.Get(
+			ctx,
+			quotaPath,
+			"",
+			params,
+			nil,
+			&resp); err != nil {
+
+			return nil, err
+		}
+
+		quotas = append(quotas, resp.Quotas...)
+
+		if resp.Resume == "" {
+			return quotas, nil
+		}
+		params = api.OrderedValues{{resumeByteArr, []byte(resp.Resume)}}
+	}
+}
+
+// QuotaInspect GETs a quota.
+func QuotaInspect(
+	ctx context.Context,
+	client api.Client,
+	id string) (*Quota, error) {
+
+	var resp QuotaList
+
+	if err := client.Get(
+		ctx,
+		quotaPath,
+		id,
+		nil,
+		nil,
+		&resp); err != nil {
+
+		return nil, err
+	}
+
+	if len(resp) == 0 {
+		return nil, nil
+	}
+
+	return resp[0], nil
+}
+
+// QuotaCreate POSTs a Quota object to the Isilon server.
+func QuotaCreate(
+	ctx context.Context,
+	client api.Client,
+	quota *Quota) (string, error) {
+
+	if quota.Path == nil || *quota.Path == "" {
+		return "", errors.New("no path set")
+	}
+	if quota.Type == nil {
+		return "", errors.New("no type set")
+	}
+	if (*quota.Type == QuotaTypeUser || *quota.Type == QuotaTypeGroup) &&
+		quota.Persona == nil {
+		return "", errors.New("no persona set")
+	}
+
+	var resp Quota
+
+	if err := client.Post(
+		ctx,
+		quotaPath,
+		"",
+		nil,
+		nil,
+		quota,
+		&resp); err != nil {
+
+		return "", err
+	}
+
+	return resp.ID, nil
+}
+
+// QuotaUpdate PUTs a Quota object to the Isilon server. A quota's path,
+// type, persona and include_snapshots properties cannot be modified and are
+// not sent.
+func QuotaUpdate(
+	ctx context.Context,
+	client api.Client,
+	quota *Quota) error {
+
+	return client.Put(
+		ctx,
+		quotaPath,
+		quota.ID,
+		nil,
+		nil,
+		&Quota{
+			Linked:                    quota.Linked,
+			ThresholdsIncludeOverhead: quota.ThresholdsIncludeOverhead,
+			Container:                 quota.Container,
+			Enforced:                  quota.Enforced,
+			Thresholds:                quota.Thresholds,
+		},
+		nil)
+}
+
+// QuotaDelete DELETEs a Quota object on the Isilon server.
+func QuotaDelete(
+	ctx context.Context,
+	client api.Client,
+	id string) error {
+
+	return client.Delete(
+		ctx,
+		quotaPath,
+		id,
+		nil,
+		nil,
+		nil)
+}
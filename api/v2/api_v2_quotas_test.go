@@ -0,0 +1,162 @@
+package v2
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/thecodeteam/goisilon/api/json"
+)
+
+func TestQuotaDecodeJSON(t *testing.T) {
+	var l QuotaList
+	if err := json.Unmarshal(getOneQuotaJSON, &l); err != nil {
+		t.Fatal(err)
+	}
+	if !assert.Len(t, l, 1) {
+		t.FailNow()
+	}
+	q := l[0]
+	assert.Equal(t, "AABpAQEAAAAAAAAAAAAAQA0AAAAAAAAA", q.ID)
+	assert.Equal(t, QuotaTypeDirectory, *q.Type)
+	assert.True(t, *q.Enforced)
+	assert.Nil(t, q.Thresholds.Advisory)
+	assert.Equal(t, int64(2000), *q.Thresholds.Soft)
+	assert.Equal(t, 3600, *q.Thresholds.SoftGrace)
+	assert.Equal(t, int64(5000), *q.Thresholds.Hard)
+	assert.True(t, *q.Thresholds.SoftExceeded)
+	assert.Equal(t, int64(2048), *q.Usage.Logical)
+}
+
+func TestQuotaUpdateEncodeJSON(t *testing.T) {
+	var (
+		hard     = int64(5000)
+		enforced = true
+		usage    = int64(1)
+	)
+	buf, err := json.Marshal(&Quota{
+		ID:         "q1",
+		Enforced:   &enforced,
+		Thresholds: &QuotaThresholds{Hard: &hard},
+		Usage:      &QuotaUsage{Logical: &usage},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t,
+		`{"enforced":true,`+
+			`"thresholds":{"advisory":null,"soft":null,"hard":5000}}`,
+		string(buf))
+}
+
+func TestPersonaQueryValue(t *testing.T) {
+	name := "akutz"
+	v, err := personaQueryValue(
+		&Persona{Type: &PPersonaTypeUser, Name: &name})
+	assert.NoError(t, err)
+	assert.Equal(t, "USER:akutz", v)
+
+	v, err = personaQueryValue(
+		&Persona{ID: &PersonaID{ID: "2000", Type: PersonaIDTypeUID}})
+	assert.NoError(t, err)
+	assert.Equal(t, "UID:2000", v)
+
+	v, err = personaQueryValue(
+		&Persona{ID: &PersonaID{ID: "wheel", Type: PersonaIDTypeGroup}})
+	assert.NoError(t, err)
+	assert.Equal(t, "GROUP:wheel", v)
+
+	_, err = personaQueryValue(&Persona{Name: &name})
+	assert.Error(t, err)
+}
+
+func TestQuotasListWithFilter(t *testing.T) {
+	c := &pagedClient{pages: []string{
+		`{"quotas":[{"id":"q1","type":"user",` +
+			`"persona":{"id":"UID:2000","name":"akutz","type":"user"}}],` +
+			`"resume":"next"}`,
+		`{"quotas":[{"id":"q2","type":"user"}],"resume":null}`,
+	}}
+	name := "akutz"
+	quotas, err := QuotasListWithFilter(context.Background(), c, &QuotaFilter{
+		Type:    &PQuotaTypeUser,
+		Persona: &Persona{Type: &PPersonaTypeUser, Name: &name},
+	})
+	assert.NoError(t, err)
+	if !assert.Len(t, quotas, 2) {
+		t.FailNow()
+	}
+	assert.Equal(t, "akutz", *quotas[0].Persona.Name)
+	assert.Equal(t, PersonaIDTypeUID, quotas[0].Persona.ID.Type)
+
+	if !assert.Len(t, c.params, 2) {
+		t.FailNow()
+	}
+	assert.Equal(t, "user", string(c.params[0][0][1]))
+	assert.Equal(t, "USER:akutz", string(c.params[0][1][1]))
+	assert.Equal(t, "resume", string(c.params[1][0][0]))
+	assert.Equal(t, "next", string(c.params[1][0][1]))
+}
+
+func TestQuotaFilterPathParams(t *testing.T) {
+	path := "/ifs/volumes/vol1"
+	params, err := (&QuotaFilter{
+		Type:                &PQuotaTypeDirectory,
+		Path:                &path,
+		RecursePathChildren: true,
+	}).params()
+	assert.NoError(t, err)
+	if !assert.Len(t, params, 3) {
+		t.FailNow()
+	}
+	assert.Equal(t, "type=directory", string(params[0][0])+"="+string(params[0][1]))
+	assert.Equal(t, "path="+path, string(params[1][0])+"="+string(params[1][1]))
+	assert.Equal(t, "recurse_path_children=true",
+		string(params[2][0])+"="+string(params[2][1]))
+
+	params, err = (&QuotaFilter{RecursePathChildren: true}).params()
+	assert.NoError(t, err)
+	assert.Empty(t, params)
+}
+
+var getOneQuotaJSON = []byte(`{
+"quotas" :
+[
+
+{
+"container" : true,
+"enforced" : true,
+"id" : "AABpAQEAAAAAAAAAAAAAQA0AAAAAAAAA",
+"include_snapshots" : false,
+"linked" : false,
+"notifications" : "default",
+"path" : "/ifs/volumes/vol1",
+"persona" : null,
+"ready" : true,
+"thresholds" :
+{
+"advisory" : null,
+"advisory_exceeded" : false,
+"advisory_last_exceeded" : null,
+"hard" : 5000,
+"hard_exceeded" : false,
+"hard_last_exceeded" : null,
+"soft" : 2000,
+"soft_exceeded" : true,
+"soft_grace" : 3600,
+"soft_last_exceeded" : 1500000000
+},
+"thresholds_include_overhead" : false,
+"type" : "directory",
+"usage" :
+{
+"inodes" : 3,
+"logical" : 2048,
+"physical" : 6144
+}
+}
+],
+"resume" : null,
+"total" : 1
+}`)
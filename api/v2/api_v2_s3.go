@@ -0,0 +1,409 @@
+package v2
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"strings"
+
+	"github.com/thecodeteam/goisilon/api"
+	"github.com/thecodeteam/goisilon/api/json"
+)
+
+// Bucket is an Isilon S3 bucket.
+type Bucket struct {
+	ID              string        `json:"id,omitmarshal"`
+	Name            *string       `json:"name,omitempty"`
+	Path            *string       `json:"path,omitempty"`
+	Owner           *string       `json:"owner,omitempty"`
+	Description     *string       `json:"description,omitempty"`
+	ACL             *[]*BucketACE `json:"acl,omitempty"`
+	CreatePath      *bool         `json:"create_path,omitempty"`
+	ObjectACLPolicy *string       `json:"object_acl_policy,omitempty"`
+	Zone            *string       `json:"zone,omitempty"`
+}
+
+// BucketACE is an entry of an S3 bucket's access control list.
+type BucketACE struct {
+	Grantee    *Persona          `json:"grantee,omitempty"`
+	Permission *BucketPermission `json:"permission,omitempty"`
+}
+
+// BucketList is a list of Isilon S3 buckets.
+type BucketList []*Bucket
+
+// MarshalJSON marshals a BucketList to JSON.
+func (l BucketList) MarshalJSON() ([]byte, error) {
+	buckets := struct {
+		Buckets []*Bucket `json:"buckets,omitempty"`
+	}{l}
+	return json.Marshal(buckets)
+}
+
+// UnmarshalJSON unmarshals a BucketList from JSON.
+func (l *BucketList) UnmarshalJSON(text []byte) error {
+	buckets := struct {
+		Buckets []*Bucket `json:"buckets,omitempty"`
+	}{}
+	if err := json.Unmarshal(text, &buckets); err != nil {
+		return err
+	}
+	*l = buckets.Buckets
+	return nil
+}
+
+type resumeableBucketList struct {
+	Buckets []*Bucket `json:"buckets,omitempty"`
+	Resume  string    `json:"resume,omitempty"`
+}
+
+// BucketPermission is a possible value used with a BucketACE's Permission
+// field.
+type BucketPermission uint8
+
+const (
+	// BucketPermissionUnknown is an unknown BucketPermission.
+	BucketPermissionUnknown BucketPermission = iota
+
+	// BucketPermissionRead allows listing the bucket's objects.
+	BucketPermissionRead
+
+	// BucketPermissionWrite allows creating and deleting the bucket's
+	// objects.
+	BucketPermissionWrite
+
+	// BucketPermissionReadACP allows reading the bucket's ACL.
+	BucketPermissionReadACP
+
+	// BucketPermissionWriteACP allows writing the bucket's ACL.
+	BucketPermissionWriteACP
+
+	// BucketPermissionFullControl grants all of the other permissions.
+	BucketPermissionFullControl
+
+	bucketPermissionCount
+)
+
+var (
+	// PBucketPermissionRead is used to grab a pointer to a const.
+	PBucketPermissionRead = BucketPermissionRead
+
+	// PBucketPermissionWrite is used to grab a pointer to a const.
+	PBucketPermissionWrite = BucketPermissionWrite
+
+	// PBucketPermissionReadACP is used to grab a pointer to a const.
+	PBucketPermissionReadACP = BucketPermissionReadACP
+
+	// PBucketPermissionWriteACP is used to grab a pointer to a const.
+	PBucketPermissionWriteACP = BucketPermissionWriteACP
+
+	// PBucketPermissionFullControl is used to grab a pointer to a const.
+	PBucketPermissionFullControl = BucketPermissionFullControl
+)
+
+const (
+	bucketPermissionUnknownStr     = "unknown"
+	bucketPermissionReadStr        = "READ"
+	bucketPermissionWriteStr       = "WRITE"
+	bucketPermissionReadACPStr     = "READ_ACP"
+	bucketPermissionWriteACPStr    = "WRITE_ACP"
+	bucketPermissionFullControlStr = "FULL_CONTROL"
+)
+
+var bucketPermissionsToStrs = [bucketPermissionCount]string{
+	bucketPermissionUnknownStr,
+	bucketPermissionReadStr,
+	bucketPermissionWriteStr,
+	bucketPermissionReadACPStr,
+	bucketPermissionWriteACPStr,
+	bucketPermissionFullControlStr,
+}
+
+// ParseBucketPermission parses a BucketPermission from a string.
+func ParseBucketPermission(text string) BucketPermission {
+	for i := BucketPermissionRead; i < bucketPermissionCount; i++ {
+		if strings.EqualFold(text, bucketPermissionsToStrs[i]) {
+			return i
+		}
+	}
+	return BucketPermissionUnknown
+}
+
+// String returns the string representation of a BucketPermission value.
+func (p BucketPermission) String() string {
+	if p < (BucketPermissionUnknown+1) || p >= bucketPermissionCount {
+		return bucketPermissionsToStrs[BucketPermissionUnknown]
+	}
+	return bucketPermissionsToStrs[p]
+}
+
+// MarshalJSON marshals a BucketPermission value to JSON.
+func (p BucketPermission) MarshalJSON() ([]byte, error) {
+	return json.Marshal(p.String())
+}
+
+// UnmarshalJSON unmarshals a BucketPermission value from JSON.
+func (p *BucketPermission) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	*p = ParseBucketPermission(s)
+	return nil
+}
+
+// S3Key is a user's S3 access key. The secret keys are only returned when a
+// key is generated.
+type S3Key struct {
+	AccessID           string `json:"access_id,omitempty"`
+	SecretKey          string `json:"secret_key,omitempty"`
+	SecretKeyTimestamp int64  `json:"secret_key_timestamp,omitempty"`
+	OldSecretKey       string `json:"old_secret_key,omitempty"`
+	OldKeyExpiry       int64  `json:"old_key_expiry,omitempty"`
+	OldKeyTimestamp    int64  `json:"old_key_timestamp,omitempty"`
+}
+
+// S3KeyGenerateRequest is the request used to generate a user's S3 key.
+type S3KeyGenerateRequest struct {
+
+	// ExistingKeyExpiryTime is the number of minutes after which the user's
+	// previous secret key expires.
+	ExistingKeyExpiryTime *int `json:"existing_key_expiry_time,omitempty"`
+}
+
+type s3KeyResp struct {
+	Keys *S3Key `json:"keys,omitempty"`
+}
+
+// BucketsList GETs all S3 buckets of an access zone. If zone is empty then
+// the buckets of the System zone are returned.
+func BucketsList(
+	ctx context.Context,
+	client api.Client,
+	zone string) ([]*Bucket, error) {
+
+	var (
+		buckets []*Bucket
+		params  = zoneParams(zone)
+	)
+
+	for {
+		var resp resumeableBucketList
+
+		if err := client.Get(
+			ctx,
+			s3BucketsPath,
+			"",
+			params,
+			nil,
+			&resp); err != nil {
+
+			return nil, err
+		}
+
+		buckets = append(buckets, resp.Buckets...)
+
+		if resp.Resume == "" {
+			return buckets, nil
+		}
+		params = api.OrderedValues{{resumeByteArr, []byte(resp.Resume)}}
+	}
+}
+
+// BucketInspect GETs an S3 bucket of an access zone. If zone is empty then
+// the bucket is in the System zone.
+func BucketInspect(
+	ctx context.Context,
+	client api.Client,
+	zone, id string) (*Bucket, error) {
+
+	var resp BucketList
+
+	if err := client.Get(
+		ctx,
+		s3BucketsPath,
+		id,
+		zoneParams(zone),
+		nil,
+		&resp); err != nil {
+
+		return nil, err
+	}
+
+	if len(resp) == 0 {
+		return nil, nil
+	}
+
+	return resp[0], nil
+}
+
+// BucketCreate POSTs a Bucket object to the Isilon server. If zone is empty
+// then the bucket is created in the System zone.
+func BucketCreate(
+	ctx context.Context,
+	client api.Client,
+	zone string,
+	bucket *Bucket) (string, error) {
+
+	if bucket.Name == nil || *bucket.Name == "" {
+		return "", errors.New("no name set")
+	}
+	if bucket.Path == nil || *bucket.Path == "" {
+		return "", errors.New("no path set")
+	}
+
+	var resp Bucket
+
+	if err := client.Post(
+		ctx,
+		s3BucketsPath,
+		"",
+		zoneParams(zone),
+		nil,
+		bucket,
+		&resp); err != nil {
+
+		return "", err
+	}
+
+	return resp.ID, nil
+}
+
+// BucketUpdate PUTs a Bucket object to the Isilon server. A bucket's name
+// and path cannot be modified. If zone is empty then the bucket is in the
+// System zone.
+func BucketUpdate(
+	ctx context.Context,
+	client api.Client,
+	zone string,
+	bucket *Bucket) error {
+
+	return client.Put(
+		ctx,
+		s3BucketsPath,
+		bucket.ID,
+		zoneParams(zone),
+		nil,
+		&Bucket{
+			Description:     bucket.Description,
+			ACL:             bucket.ACL,
+			ObjectACLPolicy: bucket.ObjectACLPolicy,
+		},
+		nil)
+}
+
+// BucketDelete DELETEs a Bucket object on the Isilon server. If zone is
+// empty then the bucket is in the System zone.
+func BucketDelete(
+	ctx context.Context,
+	client api.Client,
+	zone, id string) error {
+
+	return client.Delete(
+		ctx,
+		s3BucketsPath,
+		id,
+		zoneParams(zone),
+		nil,
+		nil)
+}
+
+// BucketACLGet GETs an S3 bucket's access control list.
+func BucketACLGet(
+	ctx context.Context,
+	client api.Client,
+	zone, id string) ([]*BucketACE, error) {
+
+	bucket, err := BucketInspect(ctx, client, zone, id)
+	if err != nil {
+		return nil, err
+	}
+	if bucket == nil || bucket.ACL == nil {
+		return nil, nil
+	}
+	return *bucket.ACL, nil
+}
+
+// BucketACLSet PUTs an S3 bucket's access control list, replacing the
+// existing list.
+func BucketACLSet(
+	ctx context.Context,
+	client api.Client,
+	zone, id string,
+	acl []*BucketACE) error {
+
+	if acl == nil {
+		acl = []*BucketACE{}
+	}
+	return BucketUpdate(ctx, client, zone, &Bucket{ID: id, ACL: &acl})
+}
+
+// S3KeyGet GETs a user's S3 access key in an access zone. The secret keys
+// are not returned. If zone is empty then the user's key in the System zone
+// is returned.
+func S3KeyGet(
+	ctx context.Context,
+	client api.Client,
+	zone, user string) (*S3Key, error) {
+
+	var resp s3KeyResp
+
+	if err := client.Get(
+		ctx,
+		s3KeysPath,
+		url.PathEscape(user),
+		zoneParams(zone),
+		nil,
+		&resp); err != nil {
+
+		return nil, err
+	}
+
+	return resp.Keys, nil
+}
+
+// S3KeyGenerate POSTs a request to generate a new S3 access key for a user
+// in an access zone. The returned key includes the new secret key. If zone
+// is empty then the key is generated in the System zone.
+func S3KeyGenerate(
+	ctx context.Context,
+	client api.Client,
+	zone, user string,
+	req *S3KeyGenerateRequest) (*S3Key, error) {
+
+	if req == nil {
+		req = &S3KeyGenerateRequest{}
+	}
+
+	var resp s3KeyResp
+
+	if err := client.Post(
+		ctx,
+		s3KeysPath,
+		url.PathEscape(user),
+		zoneParams(zone),
+		nil,
+		req,
+		&resp); err != nil {
+
+		return nil, err
+	}
+
+	return resp.Keys, nil
+}
+
+// S3KeyDelete DELETEs a user's S3 access keys in an access zone. If zone is
+// empty then the user's keys in the System zone are deleted.
+func S3KeyDelete(
+	ctx context.Context,
+	client api.Client,
+	zone, user string) error {
+
+	return client.Delete(
+		ctx,
+		s3KeysPath,
+		url.PathEscape(user),
+		zoneParams(zone),
+		nil,
+		nil)
+}
@@ -0,0 +1,110 @@
+package v2
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/thecodeteam/goisilon/api"
+	"github.com/thecodeteam/goisilon/api/json"
+)
+
+func TestBucketDecodeJSON(t *testing.T) {
+	j := `{"buckets":[{"acl":[{"grantee":{"id":"UID:2000","name":"akutz",` +
+		`"type":"user"},"permission":"FULL_CONTROL"}],"id":"vol1",` +
+		`"name":"vol1","object_acl_policy":"replace","owner":"root",` +
+		`"path":"/ifs/volumes/vol1","zone":"System"}]}`
+	var l BucketList
+	if err := json.Unmarshal([]byte(j), &l); err != nil {
+		t.Fatal(err)
+	}
+	if !assert.Len(t, l, 1) {
+		t.FailNow()
+	}
+	b := l[0]
+	assert.Equal(t, "vol1", b.ID)
+	assert.Equal(t, "/ifs/volumes/vol1", *b.Path)
+	if !assert.Len(t, *b.ACL, 1) {
+		t.FailNow()
+	}
+	ace := (*b.ACL)[0]
+	assert.Equal(t, BucketPermissionFullControl, *ace.Permission)
+	assert.Equal(t, "akutz", *ace.Grantee.Name)
+}
+
+func TestBucketUpdateEncodeJSON(t *testing.T) {
+	var (
+		name = "akutz"
+		acl  = []*BucketACE{
+			{
+				Grantee:    &Persona{Name: &name},
+				Permission: &PBucketPermissionRead,
+			},
+		}
+	)
+	buf, err := json.Marshal(&Bucket{ID: "vol1", ACL: &acl})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t,
+		`{"acl":[{"grantee":"akutz","permission":"READ"}]}`, string(buf))
+}
+
+func TestS3KeyDecodeJSON(t *testing.T) {
+	j := `{"keys":{"access_id":"1_akutz_accid","secret_key":"s3cr3t",` +
+		`"secret_key_timestamp":1600000000}}`
+	var resp s3KeyResp
+	if err := json.Unmarshal([]byte(j), &resp); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, "1_akutz_accid", resp.Keys.AccessID)
+	assert.Equal(t, "s3cr3t", resp.Keys.SecretKey)
+}
+
+// requestClient is an api.Client that records the ID and query parameters
+// of the requests it receives.
+type requestClient struct {
+	api.Client
+	ids    []string
+	params []api.OrderedValues
+}
+
+func (c *requestClient) Get(
+	ctx context.Context,
+	path, id string,
+	params api.OrderedValues, headers map[string]string,
+	resp interface{}) error {
+
+	c.ids = append(c.ids, id)
+	c.params = append(c.params, params)
+	return nil
+}
+
+func (c *requestClient) Delete(
+	ctx context.Context,
+	path, id string,
+	params api.OrderedValues, headers map[string]string,
+	resp interface{}) error {
+
+	return c.Get(ctx, path, id, params, headers, resp)
+}
+
+func (c *requestClient) Put(
+	ctx context.Context,
+	path, id string,
+	params api.OrderedValues, headers map[string]string,
+	body, resp interface{}) error {
+
+	return c.Get(ctx, path, id, params, headers, resp)
+}
+
+func TestS3KeyEscapesUserAndSetsZone(t *testing.T) {
+	c := &requestClient{}
+	_, err := S3KeyGet(context.Background(), c, "zone1", `DOMAIN\user`)
+	assert.NoError(t, err)
+	assert.NoError(t, S3KeyDelete(context.Background(), c, "", "user 1"))
+	assert.Equal(t, []string{"DOMAIN%5Cuser", "user%201"}, c.ids)
+	assert.Equal(t, "zone=zone1", c.params[0].Encode())
+	assert.Nil(t, c.params[1])
+}
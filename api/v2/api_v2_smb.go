@@ -0,0 +1,355 @@
+package v2
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"strings"
+
+	"github.com/thecodeteam/goisilon/api"
+	"github.com/thecodeteam/goisilon/api/json"
+)
+
+// Share is an Isilon SMB share.
+type Share struct {
+	ID                             string              `json:"id,omitmarshal"`
+	Name                           *string             `json:"name,omitempty"`
+	Path                           *string             `json:"path,omitempty"`
+	Description                    *string             `json:"description,omitempty"`
+	Zone                           *string             `json:"zone,omitempty"`
+	Permissions                    *[]*SharePermission `json:"permissions,omitempty"`
+	RunAsRoot                      *[]*Persona         `json:"run_as_root,omitempty"`
+	HostACL                        *[]string           `json:"host_acl,omitempty"`
+	AccessBasedEnumeration         *bool               `json:"access_based_enumeration,omitempty"`
+	AccessBasedEnumerationRootOnly *bool               `json:"access_based_enumeration_root_only,omitempty"`
+	ContinuouslyAvailable          *bool               `json:"continuously_available,omitempty"`
+	CATimeout                      *int                `json:"ca_timeout,omitempty"`
+	CAWriteIntegrity               *string             `json:"ca_write_integrity,omitempty"`
+	StrictCALockout                *bool               `json:"strict_ca_lockout,omitempty"`
+	Browsable                      *bool               `json:"browsable,omitempty"`
+	CreatePermissions              *string             `json:"create_permissions,omitempty"`
+	CSCPolicy                      *string             `json:"csc_policy,omitempty"`
+	DirectoryCreateMask            *int                `json:"directory_create_mask,omitempty"`
+	DirectoryCreateMode            *int                `json:"directory_create_mode,omitempty"`
+	FileCreateMask                 *int                `json:"file_create_mask,omitempty"`
+	FileCreateMode                 *int                `json:"file_create_mode,omitempty"`
+	HideDotFiles                   *bool               `json:"hide_dot_files,omitempty"`
+	ImpersonateGuest               *string             `json:"impersonate_guest,omitempty"`
+	ImpersonateUser                *string             `json:"impersonate_user,omitempty"`
+	NTFSACLSupport                 *bool               `json:"ntfs_acl_support,omitempty"`
+	Oplocks                        *bool               `json:"oplocks,omitempty"`
+	SMB3EncryptionEnabled          *bool               `json:"smb3_encryption_enabled,omitempty"`
+}
+
+// SharePermission maps to the ISI <smb-share-permission> type.
+type SharePermission struct {
+	Trustee        *Persona              `json:"trustee,omitempty"`
+	PermissionType *SharePermissionType  `json:"permission_type,omitempty"`
+	Permission     *SharePermissionLevel `json:"permission,omitempty"`
+}
+
+// ShareList is a list of Isilon SMB shares.
+type ShareList []*Share
+
+// MarshalJSON marshals a ShareList to JSON.
+func (l ShareList) MarshalJSON() ([]byte, error) {
+	shares := struct {
+		Shares []*Share `json:"shares,omitempty"`
+	}{l}
+	return json.Marshal(shares)
+}
+
+// UnmarshalJSON unmarshals a ShareList from JSON.
+func (l *ShareList) UnmarshalJSON(text []byte) error {
+	shares := struct {
+		Shares []*Share `json:"shares,omitempty"`
+	}{}
+	if err := json.Unmarshal(text, &shares); err != nil {
+		return err
+	}
+	*l = shares.Shares
+	return nil
+}
+
+type resumeableShareList struct {
+	Shares []*Share `json:"shares,omitempty"`
+	Resume string   `json:"resume,omitempty"`
+}
+
+// SharePermissionType is a possible value used with a SharePermission's
+// PermissionType field.
+type SharePermissionType uint8
+
+const (
+	// SharePermissionTypeUnknown is an unknown SharePermissionType.
+	SharePermissionTypeUnknown SharePermissionType = iota
+
+	// SharePermissionTypeAllow allows the permission.
+	SharePermissionTypeAllow
+
+	// SharePermissionTypeDeny denies the permission.
+	SharePermissionTypeDeny
+
+	sharePermissionTypeCount
+)
+
+var (
+	// PSharePermissionTypeAllow is used to grab a pointer to a const.
+	PSharePermissionTypeAllow = SharePermissionTypeAllow
+
+	// PSharePermissionTypeDeny is used to grab a pointer to a const.
+	PSharePermissionTypeDeny = SharePermissionTypeDeny
+)
+
+const (
+	sharePermissionTypeUnknownStr = "unknown"
+	sharePermissionTypeAllowStr   = "allow"
+	sharePermissionTypeDenyStr    = "deny"
+)
+
+var sharePermissionTypesToStrs = [sharePermissionTypeCount]string{
+	sharePermissionTypeUnknownStr,
+	sharePermissionTypeAllowStr,
+	sharePermissionTypeDenyStr,
+}
+
+// ParseSharePermissionType parses a SharePermissionType from a string.
+func ParseSharePermissionType(text string) SharePermissionType {
+	switch {
+	case strings.EqualFold(text, sharePermissionTypeAllowStr):
+		return SharePermissionTypeAllow
+	case strings.EqualFold(text, sharePermissionTypeDenyStr):
+		return SharePermissionTypeDeny
+	}
+	return SharePermissionTypeUnknown
+}
+
+// String returns the string representation of a SharePermissionType value.
+func (p SharePermissionType) String() string {
+	if p < (SharePermissionTypeUnknown+1) || p >= sharePermissionTypeCount {
+		return sharePermissionTypesToStrs[SharePermissionTypeUnknown]
+	}
+	return sharePermissionTypesToStrs[p]
+}
+
+// MarshalJSON marshals a SharePermissionType value to JSON.
+func (p SharePermissionType) MarshalJSON() ([]byte, error) {
+	return json.Marshal(p.String())
+}
+
+// UnmarshalJSON unmarshals a SharePermissionType value from JSON.
+func (p *SharePermissionType) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	*p = ParseSharePermissionType(s)
+	return nil
+}
+
+// SharePermissionLevel is a possible value used with a SharePermission's
+// Permission field.
+type SharePermissionLevel uint8
+
+const (
+	// SharePermissionLevelUnknown is an unknown SharePermissionLevel.
+	SharePermissionLevelUnknown SharePermissionLevel = iota
+
+	// SharePermissionLevelRead is the read permission.
+	SharePermissionLevelRead
+
+	// SharePermissionLevelChange is the change permission.
+	SharePermissionLevelChange
+
+	// SharePermissionLevelFull is the full control permission.
+	SharePermissionLevelFull
+
+	sharePermissionLevelCount
+)
+
+var (
+	// PSharePermissionLevelRead is used to grab a pointer to a const.
+	PSharePermissionLevelRead = SharePermissionLevelRead
+
+	// PSharePermissionLevelChange is used to grab a pointer to a const.
+	PSharePermissionLevelChange = SharePermissionLevelChange
+
+	// PSharePermissionLevelFull is used to grab a pointer to a const.
+	PSharePermissionLevelFull = SharePermissionLevelFull
+)
+
+const (
+	sharePermissionLevelUnknownStr = "unknown"
+	sharePermissionLevelReadStr    = "read"
+	sharePermissionLevelChangeStr  = "change"
+	sharePermissionLevelFullStr    = "full"
+)
+
+var sharePermissionLevelsToStrs = [sharePermissionLevelCount]string{
+	sharePermissionLevelUnknownStr,
+	sharePermissionLevelReadStr,
+	sharePermissionLevelChangeStr,
+	sharePermissionLevelFullStr,
+}
+
+// ParseSharePermissionLevel parses a SharePermissionLevel from a string.
+func ParseSharePermissionLevel(text string) SharePermissionLevel {
+	switch {
+	case strings.EqualFold(text, sharePermissionLevelReadStr):
+		return SharePermissionLevelRead
+	case strings.EqualFold(text, sharePermissionLevelChangeStr):
+		return SharePermissionLevelChange
+	case strings.EqualFold(text, sharePermissionLevelFullStr):
+		return SharePermissionLevelFull
+	}
+	return SharePermissionLevelUnknown
+}
+
+// String returns the string representation of a SharePermissionLevel value.
+func (p SharePermissionLevel) String() string {
+	if p < (SharePermissionLevelUnknown+1) || p >= sharePermissionLevelCount {
+		return sharePermissionLevelsToStrs[SharePermissionLevelUnknown]
+	}
+	return sharePermissionLevelsToStrs[p]
+}
+
+// MarshalJSON marshals a SharePermissionLevel value to JSON.
+func (p SharePermissionLevel) MarshalJSON() ([]byte, error) {
+	return json.Marshal(p.String())
+}
+
+// UnmarshalJSON unmarshals a SharePermissionLevel value from JSON.
+func (p *SharePermissionLevel) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	*p = ParseSharePermissionLevel(s)
+	return nil
+}
+
+// SharesList GETs all SMB shares.
+func SharesList(
+	ctx context.Context,
+	client api.Client) ([]*Share, error) {
+
+	var (
+		shares []*Share
+		params api.OrderedValues
+	)
+
+	for {
+		var resp resumeableShareList
+
+		if err := client.Get(
+			ctx,
+			sharesPath,
+			"",
+			params,
+			nil,
+			&resp); err != nil {
+
+			return nil, err
+		}
+
+		shares = append(shares, resp.Shares...)
+
+		if resp.Resume == "" {
+			return shares, nil
+		}
+		params = api.OrderedValues{{resumeByteArr, []byte(resp.Resume)}}
+	}
+}
+
+// shareID escapes a share's ID or name so that it may be used as the ID in a
+// request's path.
+func shareID(id string) string {
+	return url.PathEscape(id)
+}
+
+// ShareInspect GETs an SMB share.
+func ShareInspect(
+	ctx context.Context,
+	client api.Client,
+	id string) (*Share, error) {
+
+	var resp ShareList
+
+	if err := client.Get(
+		ctx,
+		sharesPath,
+		shareID(id),
+		nil,
+		nil,
+		&resp); err != nil {
+
+		return nil, err
+	}
+
+	if len(resp) == 0 {
+		return nil, nil
+	}
+
+	return resp[0], nil
+}
+
+// ShareCreate POSTs a Share object to the Isilon server.
+func ShareCreate(
+	ctx context.Context,
+	client api.Client,
+	share *Share) (string, error) {
+
+	if share.Name == nil || *share.Name == "" {
+		return "", errors.New("no name set")
+	}
+	if share.Path == nil || *share.Path == "" {
+		return "", errors.New("no path set")
+	}
+
+	var resp Share
+
+	if err := client.Post(
+		ctx,
+		sharesPath,
+		"",
+		nil,
+		nil,
+		share,
+		&resp); err != nil {
+
+		return "", err
+	}
+
+	return resp.ID, nil
+}
+
+// ShareUpdate PUTs a Share object to the Isilon server.
+func ShareUpdate(
+	ctx context.Context,
+	client api.Client,
+	share *Share) error {
+
+	return client.Put(
+		ctx,
+		sharesPath,
+		shareID(share.ID),
+		nil,
+		nil,
+		share,
+		nil)
+}
+
+// ShareDelete DELETEs a Share object on the Isilon server.
+func ShareDelete(
+	ctx context.Context,
+	client api.Client,
+	id string) error {
+
+	return client.Delete(
+		ctx,
+		sharesPath,
+		shareID(id),
+		nil,
+		nil,
+		nil)
+}
@@ -0,0 +1,127 @@
+package v2
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/thecodeteam/goisilon/api/json"
+)
+
+func TestShareEncodeJSON(t *testing.T) {
+	var (
+		name  = "vol1"
+		path  = "/ifs/volumes/vol1"
+		abe   = true
+		perms = []*SharePermission{
+			{
+				Trustee:        &Persona{Name: &name},
+				PermissionType: &PSharePermissionTypeAllow,
+				Permission:     &PSharePermissionLevelRead,
+			},
+		}
+	)
+	sh := &Share{
+		ID:                     "vol1",
+		Name:                   &name,
+		Path:                   &path,
+		Permissions:            &perms,
+		AccessBasedEnumeration: &abe,
+	}
+	buf, err := json.Marshal(sh)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t,
+		`{"name":"vol1","path":"/ifs/volumes/vol1",`+
+			`"permissions":[{"trustee":"vol1","permission_type":"allow",`+
+			`"permission":"read"}],"access_based_enumeration":true}`,
+		string(buf))
+}
+
+func TestShareDecodeJSON(t *testing.T) {
+	var shList ShareList
+	if err := json.Unmarshal(getOneShareJSON, &shList); err != nil {
+		t.Fatal(err)
+	}
+	if !assert.Len(t, shList, 1) {
+		t.FailNow()
+	}
+	sh := shList[0]
+
+	assert.Equal(t, "vol1", sh.ID)
+	assert.Equal(t, "/ifs/volumes/vol1", *sh.Path)
+	assert.Equal(t, []string{"allow:10.0.0.0/8", "deny:ALL"}, *sh.HostACL)
+	assert.True(t, *sh.ContinuouslyAvailable)
+	assert.Equal(t, 120, *sh.CATimeout)
+	assert.Equal(t, "write-read-coherent", *sh.CAWriteIntegrity)
+	assert.False(t, *sh.AccessBasedEnumeration)
+	if !assert.Len(t, *sh.Permissions, 1) {
+		t.FailNow()
+	}
+	p := (*sh.Permissions)[0]
+	assert.Equal(t, SharePermissionTypeDeny, *p.PermissionType)
+	assert.Equal(t, SharePermissionLevelFull, *p.Permission)
+	assert.Equal(t, PersonaIDTypeSID, p.Trustee.ID.Type)
+	assert.Equal(t, "S-1-1-0", p.Trustee.ID.ID)
+	assert.Equal(t, "Everyone", *p.Trustee.Name)
+}
+
+func TestSharePermissionParse(t *testing.T) {
+	assert.Equal(t, SharePermissionTypeAllow, ParseSharePermissionType("ALLOW"))
+	assert.Equal(t, SharePermissionTypeUnknown, ParseSharePermissionType("x"))
+	assert.Equal(t, SharePermissionLevelChange, ParseSharePermissionLevel("change"))
+	assert.Equal(t, "unknown", SharePermissionLevel(99).String())
+}
+
+var getOneShareJSON = []byte(`{
+"shares" :
+[
+
+{
+"access_based_enumeration" : false,
+"access_based_enumeration_root_only" : false,
+"browsable" : true,
+"ca_timeout" : 120,
+"ca_write_integrity" : "write-read-coherent",
+"continuously_available" : true,
+"description" : "",
+"host_acl" : [ "allow:10.0.0.0/8", "deny:ALL" ],
+"id" : "vol1",
+"name" : "vol1",
+"path" : "/ifs/volumes/vol1",
+"permissions" :
+[
+
+{
+"permission" : "full",
+"permission_type" : "deny",
+"trustee" :
+{
+"id" : "SID:S-1-1-0",
+"name" : "Everyone",
+"type" : "wellknown"
+}
+}
+],
+"run_as_root" : [],
+"strict_ca_lockout" : true,
+"zone" : "System"
+}
+]
+}`)
+
+func TestShareEscapesID(t *testing.T) {
+	var (
+		c    = &requestClient{}
+		ctx  = context.Background()
+		name = "my share#1"
+	)
+	_, err := ShareInspect(ctx, c, name)
+	assert.NoError(t, err)
+	assert.NoError(t, ShareUpdate(ctx, c, &Share{ID: name}))
+	assert.NoError(t, ShareDelete(ctx, c, name))
+	assert.Equal(t, []string{
+		"my%20share%231", "my%20share%231", "my%20share%231"}, c.ids)
+}
@@ -0,0 +1,210 @@
+package v2
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"strconv"
+
+	"github.com/thecodeteam/goisilon/api"
+	"github.com/thecodeteam/goisilon/api/json"
+)
+
+// SnapshotSchedule is an Isilon SnapshotIQ schedule that periodically takes
+// snapshots of a path.
+type SnapshotSchedule struct {
+	ID           int     `json:"id,omitmarshal"`
+	Name         *string `json:"name,omitempty"`
+	Path         *string `json:"path,omitempty"`
+	Pattern      *string `json:"pattern,omitempty"`
+	Schedule     *string `json:"schedule,omitempty"`
+	Duration     *int    `json:"duration,omitempty"`
+	Alias        *string `json:"alias,omitempty"`
+	NextRun      *int64  `json:"next_run,omitmarshal"`
+	NextSnapshot *string `json:"next_snapshot,omitmarshal"`
+}
+
+// SnapshotScheduleUpdateReq is the body of a request that replaces a
+// snapshot schedule's settings. Unlike a SnapshotSchedule, its Duration and
+// Alias are always sent so that a zero duration or an empty alias clears the
+// schedule's expiration or alias.
+type SnapshotScheduleUpdateReq struct {
+	Pattern  *string `json:"pattern,omitempty"`
+	Schedule *string `json:"schedule,omitempty"`
+	Duration int     `json:"duration"`
+	Alias    string  `json:"alias"`
+}
+
+// SnapshotScheduleList is a list of Isilon SnapshotIQ schedules.
+type SnapshotScheduleList []*SnapshotSchedule
+
+// MarshalJSON marshals a SnapshotScheduleList to JSON.
+func (l SnapshotScheduleList) MarshalJSON() ([]byte, error) {
+	schedules := struct {
+		Schedules []*SnapshotSchedule `json:"schedules,omitempty"`
+	}{l}
+	return json.Marshal(schedules)
+}
+
+// UnmarshalJSON unmarshals a SnapshotScheduleList from JSON.
+func (l *SnapshotScheduleList) UnmarshalJSON(text []byte) error {
+	schedules := struct {
+		Schedules []*SnapshotSchedule `json:"schedules,omitempty"`
+	}{}
+	if err := json.Unmarshal(text, &schedules); err != nil {
+		return err
+	}
+	*l = schedules.Schedules
+	return nil
+}
+
+type resumeableSnapshotScheduleList struct {
+	Schedules []*SnapshotSchedule `json:"schedules,omitempty"`
+	Resume    string              `json:"resume,omitempty"`
+}
+
+// SnapshotSchedulesList GETs all snapshot schedules.
+func SnapshotSchedulesList(
+	ctx context.Context,
+	client api.Client) ([]*SnapshotSchedule, error) {
+
+	var (
+		schedules []*SnapshotSchedule
+		params    api.OrderedValues
+	)
+
+	for {
+		var resp resumeableSnapshotScheduleList
+
+		if err := client.Get(
+			ctx,
+			snapshotSchedulesPath,
+			"",
+			params,
+			nil,
+			&resp); err != nil {
+
+			return nil, err
+		}
+
+		schedules = append(schedules, resp.Schedules...)
+
+		if resp.Resume == "" {
+			return schedules, nil
+		}
+		params = api.OrderedValues{{resumeByteArr, []byte(resp.Resume)}}
+	}
+}
+
+// SnapshotScheduleInspect GETs a snapshot schedule by its ID or name.
+func SnapshotScheduleInspect(
+	ctx context.Context,
+	client api.Client,
+	id string) (*SnapshotSchedule, error) {
+
+	var resp SnapshotScheduleList
+
+	if err := client.Get(
+		ctx,
+		snapshotSchedulesPath,
+		url.PathEscape(id),
+		nil,
+		nil,
+		&resp); err != nil {
+
+		return nil, err
+	}
+
+	if len(resp) == 0 {
+		return nil, nil
+	}
+
+	return resp[0], nil
+}
+
+// SnapshotScheduleCreate POSTs a SnapshotSchedule object to the Isilon
+// server and returns the schedule's ID.
+func SnapshotScheduleCreate(
+	ctx context.Context,
+	client api.Client,
+	schedule *SnapshotSchedule) (int, error) {
+
+	if schedule.Name == nil || *schedule.Name == "" {
+		return 0, errors.New("no name set")
+	}
+	if schedule.Path == nil || *schedule.Path == "" {
+		return 0, errors.New("no path set")
+	}
+	if schedule.Pattern == nil || *schedule.Pattern == "" {
+		return 0, errors.New("no pattern set")
+	}
+	if schedule.Schedule == nil || *schedule.Schedule == "" {
+		return 0, errors.New("no schedule set")
+	}
+
+	var resp SnapshotSchedule
+
+	if err := client.Post(
+		ctx,
+		snapshotSchedulesPath,
+		"",
+		nil,
+		nil,
+		schedule,
+		&resp); err != nil {
+
+		return 0, err
+	}
+
+	return resp.ID, nil
+}
+
+// SnapshotScheduleUpdate PUTs a SnapshotSchedule object to the Isilon
+// server. Only the schedule's non-nil fields are modified.
+func SnapshotScheduleUpdate(
+	ctx context.Context,
+	client api.Client,
+	schedule *SnapshotSchedule) error {
+
+	return client.Put(
+		ctx,
+		snapshotSchedulesPath,
+		strconv.Itoa(schedule.ID),
+		nil,
+		nil,
+		schedule,
+		nil)
+}
+
+// SnapshotScheduleReplace PUTs a SnapshotScheduleUpdateReq to the Isilon
+// server. The schedule's duration and alias are always modified.
+func SnapshotScheduleReplace(
+	ctx context.Context,
+	client api.Client,
+	id int,
+	req *SnapshotScheduleUpdateReq) error {
+
+	return client.Put(
+		ctx,
+		snapshotSchedulesPath,
+		strconv.Itoa(id),
+		nil,
+		nil,
+		req,
+		nil)
+}
+
+// SnapshotScheduleDelete DELETEs a snapshot schedule by its ID or name.
+func SnapshotScheduleDelete(
+	ctx context.Context,
+	client api.Client,
+	id string) error {
+
+	return client.Delete(
+		ctx,
+		snapshotSchedulesPath,
+		url.PathEscape(id),
+		nil,
+		nil,
+		nil)
+}
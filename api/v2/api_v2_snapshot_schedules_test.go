@@ -0,0 +1,58 @@
+package v2
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/thecodeteam/goisilon/api/json"
+)
+
+func TestSnapshotSchedulesList(t *testing.T) {
+	c := &pagedClient{pages: []string{
+		`{"schedules":[{"id":1,"name":"vol1","path":"/ifs/volumes/vol1",` +
+			`"pattern":"vol1_%Y","schedule":"every 1 days at 12:00 AM",` +
+			`"duration":604800,"alias":null,"next_run":1500000000,` +
+			`"next_snapshot":"vol1_2017"}],"resume":"next"}`,
+		`{"schedules":[{"id":2,"name":"vol2"}],"resume":null}`,
+	}}
+	schedules, err := SnapshotSchedulesList(context.Background(), c)
+	assert.NoError(t, err)
+	if !assert.Len(t, schedules, 2) {
+		t.FailNow()
+	}
+	s := schedules[0]
+	assert.Equal(t, 1, s.ID)
+	assert.Equal(t, "/ifs/volumes/vol1", *s.Path)
+	assert.Equal(t, 604800, *s.Duration)
+	assert.Nil(t, s.Alias)
+	assert.Equal(t, int64(1500000000), *s.NextRun)
+	assert.Equal(t, 2, schedules[1].ID)
+	assert.Equal(t, "next", string(c.params[1][0][1]))
+}
+
+func TestSnapshotScheduleEncodeJSON(t *testing.T) {
+	name := "vol1"
+	buf, err := json.Marshal(&SnapshotSchedule{ID: 1, Name: &name})
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"name":"vol1"}`, string(buf))
+}
+
+func TestSnapshotScheduleUpdateReqEncodeJSON(t *testing.T) {
+	schedule := "every 1 days at 12:00 AM"
+	buf, err := json.Marshal(&SnapshotScheduleUpdateReq{Schedule: &schedule})
+	assert.NoError(t, err)
+	assert.JSONEq(t,
+		`{"schedule":"every 1 days at 12:00 AM","duration":0,"alias":""}`,
+		string(buf))
+}
+
+func TestSnapshotScheduleInspectEscapesName(t *testing.T) {
+	c := &requestClient{}
+	_, err := SnapshotScheduleInspect(context.Background(), c, "daily vol1")
+	assert.NoError(t, err)
+	assert.NoError(t,
+		SnapshotScheduleDelete(context.Background(), c, "daily vol1"))
+	assert.Equal(t, []string{"daily%20vol1", "daily%20vol1"}, c.ids)
+}
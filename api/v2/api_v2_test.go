@@ -0,0 +1,69 @@
+package v2
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/thecodeteam/goisilon/api"
+	"github.com/thecodeteam/goisilon/api/json"
+)
+
+type volumesPathClient struct {
+	api.Client
+	volp string
+}
+
+func (c *volumesPathClient) VolumesPath() string {
+	return c.volp
+}
+
+func TestRealVolumeSnapshotPath(t *testing.T) {
+	c := &volumesPathClient{volp: "/ifs/volumes"}
+	assert.Equal(
+		t, "namespace/ifs/.snapshot/snap1/volumes",
+		realVolumeSnapshotPath(c, "snap1"))
+	assert.Equal(
+		t, "/ifs/.snapshot/snap1/volumes/vol1",
+		VolumeSnapshotPath(c, "snap1", "vol1"))
+
+	c.volp = "/ifs/gold/volumes"
+	assert.Equal(
+		t, "namespace/ifs/.snapshot/snap1/gold/volumes",
+		realVolumeSnapshotPath(c, "snap1"))
+
+	c.volp = "/ifs"
+	assert.Equal(
+		t, "namespace/ifs/.snapshot/snap1",
+		realVolumeSnapshotPath(c, "snap1"))
+}
+
+func TestSnapshotPath(t *testing.T) {
+	assert.Equal(
+		t, "/ifs/.snapshot/snap1/volumes/vol1",
+		SnapshotPath("snap1", "/ifs/volumes/vol1"))
+	assert.Equal(
+		t, "/ifs/.snapshot/snap1/vol1", SnapshotPath("snap1", "/ifs/vol1/"))
+	assert.Equal(t, "/ifs/.snapshot/snap1", SnapshotPath("snap1", "/ifs"))
+}
+
+// pagedClient is an api.Client that responds to GET requests with a series
+// of pages and records the query parameters of each request.
+type pagedClient struct {
+	api.Client
+	pages  []string
+	params []api.OrderedValues
+}
+
+func (c *pagedClient) Get(
+	ctx context.Context,
+	path, id string,
+	params api.OrderedValues, headers map[string]string,
+	resp interface{}) error {
+
+	c.params = append(c.params, params)
+	page := c.pages[0]
+	c.pages = c.pages[1:]
+	return json.Unmarshal([]byte(page), resp)
+}
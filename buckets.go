@@ -0,0 +1,186 @@
+package goisilon
+
+import (
+	"context"
+	"errors"
+	"path"
+	"strings"
+
+	api "github.com/thecodeteam/goisilon/api/v2"
+)
+
+type BucketList []*api.Bucket
+type Bucket *api.Bucket
+type S3Key *api.S3Key
+
+// GetBuckets returns a list of all S3 buckets in an access zone. If zone is
+// empty then the buckets of the System zone are returned.
+func (c *Client) GetBuckets(
+	ctx context.Context, zone string) (BucketList, error) {
+
+	return api.BucketsList(ctx, c.API, zone)
+}
+
+// GetBucket returns the S3 bucket with the provided name in an access zone.
+func (c *Client) GetBucket(
+	ctx context.Context, zone, name string) (Bucket, error) {
+
+	return api.BucketInspect(ctx, c.API, zone, name)
+}
+
+// CreateBucket creates an S3 bucket in an access zone and returns its ID.
+func (c *Client) CreateBucket(
+	ctx context.Context, zone string, bucket *api.Bucket) (string, error) {
+
+	return api.BucketCreate(ctx, c.API, zone, bucket)
+}
+
+// UpdateBucket updates an S3 bucket's description, ACL and object ACL
+// policy. Only the bucket's non-nil fields are modified.
+func (c *Client) UpdateBucket(
+	ctx context.Context, zone string, bucket *api.Bucket) error {
+
+	return api.BucketUpdate(ctx, c.API, zone, bucket)
+}
+
+// DeleteBucket deletes the S3 bucket with the provided name in an access
+// zone. The bucket's data is not removed.
+func (c *Client) DeleteBucket(ctx context.Context, zone, name string) error {
+	return api.BucketDelete(ctx, c.API, zone, name)
+}
+
+// GetBucketACL returns the access control list of the S3 bucket with the
+// provided name.
+func (c *Client) GetBucketACL(
+	ctx context.Context, zone, name string) ([]*api.BucketACE, error) {
+
+	return api.BucketACLGet(ctx, c.API, zone, name)
+}
+
+// SetBucketACL replaces the access control list of the S3 bucket with the
+// provided name.
+func (c *Client) SetBucketACL(
+	ctx context.Context, zone, name string, acl ...*api.BucketACE) error {
+
+	return api.BucketACLSet(ctx, c.API, zone, name, acl)
+}
+
+// GetS3Key returns a user's S3 access key in an access zone without its
+// secret. User names may include a domain, such as "DOMAIN\user".
+func (c *Client) GetS3Key(
+	ctx context.Context, zone, user string) (S3Key, error) {
+
+	return api.S3KeyGet(ctx, c.API, zone, user)
+}
+
+// GenerateS3Key generates a new S3 access key for a user in an access zone
+// and returns it, including its secret. If the user already has a key then
+// its secret expires after the provided number of minutes. If expiry is
+// zero, the cluster's default is used.
+func (c *Client) GenerateS3Key(
+	ctx context.Context, zone, user string, expiry int) (S3Key, error) {
+
+	req := &api.S3KeyGenerateRequest{}
+	if expiry > 0 {
+		req.ExistingKeyExpiryTime = &expiry
+	}
+	return api.S3KeyGenerate(ctx, c.API, zone, user, req)
+}
+
+// DeleteS3Key deletes a user's S3 access keys in an access zone.
+func (c *Client) DeleteS3Key(ctx context.Context, zone, user string) error {
+	return api.S3KeyDelete(ctx, c.API, zone, user)
+}
+
+// GetBucketsByVolume returns the S3 buckets in an access zone with a path
+// for the provided volume name. Buckets cannot be filtered by path, so all
+// of the zone's buckets are listed.
+func (c *Client) GetBucketsByVolume(
+	ctx context.Context, zone, name string) (BucketList, error) {
+
+	buckets, err := api.BucketsList(ctx, c.API, zone)
+	if err != nil {
+		return nil, err
+	}
+	var (
+		volBuckets BucketList
+		volPath    = c.API.VolumePath(name)
+	)
+	for _, b := range buckets {
+		if b.Path != nil && path.Clean(*b.Path) == volPath {
+			volBuckets = append(volBuckets, b)
+		}
+	}
+	return volBuckets, nil
+}
+
+// BucketVolume makes the volume with a given name available over S3 as a
+// bucket in the System zone with the provided name and returns the bucket's
+// ID. See BucketVolumeInZone.
+func (c *Client) BucketVolume(
+	ctx context.Context, volumeName, bucketName string) (string, error) {
+
+	return c.BucketVolumeInZone(ctx, "", volumeName, bucketName)
+}
+
+// BucketVolumeInZone makes the volume with a given name available over S3
+// as a bucket in an access zone with the provided name and returns the
+// bucket's ID. If the name is empty then the bucket is named after the
+// volume. If a bucket with the name already exists for the volume then its
+// ID is returned.
+func (c *Client) BucketVolumeInZone(
+	ctx context.Context,
+	zone, volumeName, bucketName string) (string, error) {
+
+	if bucketName == "" {
+		bucketName = bucketNameForVolume(volumeName)
+	}
+	volPath := c.API.VolumePath(volumeName)
+
+	// a bucket's ID is its name, so an existing bucket is found directly
+	bucket, err := api.BucketInspect(ctx, c.API, zone, bucketName)
+	if err != nil && !isNotFound(err) {
+		return "", err
+	}
+	if err == nil && bucket != nil {
+		if bucket.Path == nil || path.Clean(*bucket.Path) != volPath {
+			return "", errors.New(
+				"bucket belongs to another path: " + bucketName)
+		}
+		return bucket.ID, nil
+	}
+
+	return api.BucketCreate(
+		ctx, c.API, zone,
+		&api.Bucket{Name: &bucketName, Path: &volPath})
+}
+
+// UnbucketVolume deletes all of the S3 buckets in the System zone for the
+// volume with a given name.
+func (c *Client) UnbucketVolume(ctx context.Context, volumeName string) error {
+	return c.UnbucketVolumeInZone(ctx, "", volumeName)
+}
+
+// UnbucketVolumeInZone deletes all of the S3 buckets in an access zone for
+// the volume with a given name.
+func (c *Client) UnbucketVolumeInZone(
+	ctx context.Context, zone, volumeName string) error {
+
+	buckets, err := c.GetBucketsByVolume(ctx, zone, volumeName)
+	if err != nil {
+		return err
+	}
+	for _, b := range buckets {
+		if err := api.BucketDelete(ctx, c.API, zone, b.ID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// bucketNameForVolume returns a valid S3 bucket name for a volume. Bucket
+// names are lower case and may not contain slashes or underscores.
+func bucketNameForVolume(name string) string {
+	return strings.NewReplacer("/", "-", "_", "-").Replace(
+		strings.ToLower(strings.Trim(name, "/")))
+}
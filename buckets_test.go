@@ -0,0 +1,68 @@
+package goisilon
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBucketVolume(t *testing.T) {
+	volumeName := "test_bucket_volume"
+
+	_, err := client.CreateVolume(defaultCtx, volumeName)
+	assertNoError(t, err)
+	defer client.DeleteVolume(defaultCtx, volumeName)
+
+	id, err := client.BucketVolume(defaultCtx, volumeName, "")
+	assertNoError(t, err)
+	defer client.UnbucketVolume(defaultCtx, volumeName)
+
+	// bucketing the volume again returns the existing bucket
+	id2, err := client.BucketVolume(defaultCtx, volumeName, "")
+	assertNoError(t, err)
+	assert.Equal(t, id, id2)
+
+	bucket, err := client.GetBucket(defaultCtx, "", id)
+	assertNoError(t, err)
+	assertNotNil(t, bucket)
+	assert.Equal(t, client.API.VolumePath(volumeName), *bucket.Path)
+
+	assertNoError(t, client.UnbucketVolume(defaultCtx, volumeName))
+	buckets, err := client.GetBucketsByVolume(defaultCtx, "", volumeName)
+	assertNoError(t, err)
+	assert.Len(t, buckets, 0)
+}
+
+func TestBucketNameForVolume(t *testing.T) {
+	assert.Equal(t, "test-bucket-volume",
+		bucketNameForVolume("Test_Bucket_Volume"))
+	assert.Equal(t, "a-b", bucketNameForVolume("/a/b/"))
+}
+
+func TestBucketVolumeInspectsBucketByName(t *testing.T) {
+	var requests []string
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.Path+"?"+r.URL.RawQuery)
+		switch r.URL.Path {
+		case "/platform/10/protocols/s3/buckets/vol1":
+			w.Write([]byte(`{"buckets":[` +
+				`{"id":"vol1","name":"vol1","path":"/ifs/volumes/vol1"}]}`))
+		case "/platform/10/protocols/s3/buckets/vol2":
+			w.Write([]byte(`{"buckets":[` +
+				`{"id":"vol2","name":"vol2","path":"/ifs/volumes/other"}]}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"errors":[{"message":"not found"}]}`))
+		}
+	}, nil)
+
+	id, err := c.BucketVolumeInZone(defaultCtx, "zone1", "vol1", "")
+	assertNoError(t, err)
+	assert.Equal(t, "vol1", id)
+	assert.Equal(t, []string{
+		"GET /platform/10/protocols/s3/buckets/vol1?zone=zone1"}, requests)
+
+	_, err = c.BucketVolumeInZone(defaultCtx, "zone1", "vol2", "")
+	assertError(t, err)
+}
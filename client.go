@@ -3,7 +3,9 @@ package goisilon
 import (
 	"context"
 	"os"
+	"sort"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/thecodeteam/goisilon/api"
@@ -18,14 +20,35 @@ type Client struct {
 
 func NewClient(ctx context.Context) (*Client, error) {
 	insecure, _ := strconv.ParseBool(os.Getenv("GOISILON_INSECURE"))
-	return NewClientWithArgs(
+	timeout, _ := time.ParseDuration(os.Getenv("GOISILON_TIMEOUT"))
+	return NewClientWithOptions(
 		ctx,
 		os.Getenv("GOISILON_ENDPOINT"),
-		insecure,
 		os.Getenv("GOISILON_USERNAME"),
 		os.Getenv("GOISILON_GROUP"),
 		os.Getenv("GOISILON_PASSWORD"),
-		os.Getenv("GOISILON_VOLUMEPATH"))
+		&api.ClientOptions{
+			Insecure:    insecure,
+			VolumesPath: os.Getenv("GOISILON_VOLUMEPATH"),
+			VolumeRoots: parseVolumeRoots(os.Getenv("GOISILON_VOLUMEROOTS")),
+			Timeout:     timeout,
+		})
+}
+
+// parseVolumeRoots parses a comma-separated list of name=path pairs.
+func parseVolumeRoots(s string) map[string]string {
+	if s == "" {
+		return nil
+	}
+	roots := map[string]string{}
+	for _, kv := range strings.Split(s, ",") {
+		parts := strings.SplitN(strings.TrimSpace(kv), "=", 2)
+		if len(parts) != 2 {
+			continue
+		}
+		roots[parts[0]] = parts[1]
+	}
+	return roots
 }
 
 func NewClientWithArgs(
@@ -36,16 +59,101 @@ func NewClientWithArgs(
 
 	timeout, _ := time.ParseDuration(os.Getenv("GOISILON_TIMEOUT"))
 
-	client, err := api.New(
-		ctx, endpoint, user, pass, group,
+	return NewClientWithOptions(
+		ctx, endpoint, user, group, pass,
 		&api.ClientOptions{
 			Insecure:    insecure,
 			VolumesPath: volumesPath,
 			Timeout:     timeout,
 		})
+}
+
+// NewClientWithOptions returns a new client using the provided API client
+// options.
+func NewClientWithOptions(
+	ctx context.Context,
+	endpoint, user, group, pass string,
+	opts *api.ClientOptions) (*Client, error) {
+
+	client, err := api.New(ctx, endpoint, user, pass, group, opts)
 	if err != nil {
 		return nil, err
 	}
 
 	return &Client{client}, err
 }
+
+// WithVolumeRoot returns a copy of the client whose volume, export, quota,
+// and snapshot operations target the provided volume root. The root may be
+// the name of one of the client's volume roots or an absolute path beneath
+// /ifs. An empty root selects the client's default volumes path.
+func (c *Client) WithVolumeRoot(root string) (*Client, error) {
+	client, err := c.API.WithVolumeRoot(root)
+	if err != nil {
+		return nil, err
+	}
+	return &Client{client}, nil
+}
+
+// VolumeRootForPath returns the name of the volume root and the name of the
+// volume that contain the provided absolute path. The name of the volume
+// root is empty if the path belongs to the client's default volumes path.
+// The most specific volume root is selected when volume roots are nested.
+// When roots share a path, the default volumes path is preferred, followed
+// by the named roots in order of their names.
+func (c *Client) VolumeRootForPath(p string) (string, string, bool) {
+	var (
+		root string
+		name string
+		plen = -1
+	)
+	for _, r := range c.volumeRoots() {
+		if len(r.path) <= plen || !strings.HasPrefix(p, r.path+"/") {
+			continue
+		}
+		root = r.name
+		name = strings.SplitN(p[len(r.path)+1:], "/", 2)[0]
+		plen = len(r.path)
+	}
+	return root, name, plen >= 0
+}
+
+// volumeRoot is a volume root and the path of its volumes.
+type volumeRoot struct {
+	name string
+	path string
+}
+
+// volumeRoots returns the client's volume roots in a deterministic order:
+// the default volumes path, the named roots sorted by name, and finally the
+// client's own volumes path, named after the path, if it is none of the
+// others. Each name may be passed to WithVolumeRoot.
+func (c *Client) volumeRoots() []volumeRoot {
+	var (
+		named = c.API.VolumeRoots()
+		names = make([]string, 0, len(named))
+		roots = []volumeRoot{{path: c.defaultVolumesPath()}}
+	)
+	for k := range named {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	for _, k := range names {
+		roots = append(roots, volumeRoot{name: k, path: named[k]})
+	}
+	vp := c.API.VolumesPath()
+	for _, r := range roots {
+		if r.path == vp {
+			return roots
+		}
+	}
+	return append(roots, volumeRoot{name: vp, path: vp})
+}
+
+func (c *Client) defaultVolumesPath() string {
+	client, err := c.API.WithVolumeRoot("")
+	if err != nil {
+		return c.API.VolumesPath()
+	}
+	return client.VolumesPath()
+}
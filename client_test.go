@@ -1,9 +1,12 @@
 package goisilon
 
 import (
+	"net/http"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+
+	"github.com/thecodeteam/goisilon/api"
 )
 
 func TestNewClient(t *testing.T) {
@@ -11,3 +14,47 @@ func TestNewClient(t *testing.T) {
 	assert.NotZero(t, client.API.APIVersion())
 	t.Logf("api version=%d", client.API.APIVersion())
 }
+
+func TestClientWithVolumeRoot(t *testing.T) {
+	rc, err := client.WithVolumeRoot("/ifs/test_volume_root")
+	assertNoError(t, err)
+	assert.Equal(t, "/ifs/test_volume_root", rc.API.VolumesPath())
+
+	root, name, ok := rc.VolumeRootForPath(client.API.VolumePath("vol1/a"))
+	assert.True(t, ok)
+	assert.Equal(t, "", root)
+	assert.Equal(t, "vol1", name)
+}
+
+func TestClientVolumeRootForPathTies(t *testing.T) {
+	c := newTestClient(t, http.NotFound, &api.ClientOptions{
+		VolumesPath: "/ifs/volumes",
+		VolumeRoots: map[string]string{
+			"zeta":   "/ifs/volumes",
+			"silver": "/ifs/tier/volumes",
+			"gold":   "/ifs/tier/volumes",
+			"nested": "/ifs/tier/volumes/nested",
+		},
+	})
+
+	// resolve repeatedly so that map iteration order cannot decide a tie
+	for i := 0; i < 20; i++ {
+		root, name, ok := c.VolumeRootForPath("/ifs/volumes/vol1/a")
+		assert.True(t, ok)
+		assert.Equal(t, "", root)
+		assert.Equal(t, "vol1", name)
+
+		root, name, ok = c.VolumeRootForPath("/ifs/tier/volumes/vol2")
+		assert.True(t, ok)
+		assert.Equal(t, "gold", root)
+		assert.Equal(t, "vol2", name)
+
+		root, name, ok = c.VolumeRootForPath("/ifs/tier/volumes/nested/vol3")
+		assert.True(t, ok)
+		assert.Equal(t, "nested", root)
+		assert.Equal(t, "vol3", name)
+	}
+
+	_, _, ok := c.VolumeRootForPath("/ifs/other/vol4")
+	assert.False(t, ok)
+}
@@ -0,0 +1,359 @@
+package goisilon
+
+import (
+	"archive/tar"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	apiv2 "github.com/thecodeteam/goisilon/api/v2"
+)
+
+// DownloadOptions are the options used when downloading a volume.
+type DownloadOptions struct {
+
+	// Snapshot is the name of the snapshot from which to read the volume. If
+	// empty, the live volume is read.
+	Snapshot string
+
+	// Include is a list of glob patterns. If not empty, only files matching
+	// at least one of the patterns are downloaded. A pattern without a slash
+	// is matched against a file's base name, otherwise it is matched against
+	// the file's path relative to the volume.
+	Include []string
+
+	// Exclude is a list of glob patterns matched in the same manner as
+	// Include. Files and directories matching any of the patterns are
+	// skipped, as are the descendants of matching directories.
+	Exclude []string
+
+	// Workers is the number of files downloaded concurrently by
+	// DownloadVolume. If zero, ConcurrentHTTPConnections is used.
+	Workers int
+}
+
+// volumeEntry is a file or directory inside of a volume.
+type volumeEntry struct {
+	rel   string
+	dir   bool
+	size  int64
+	mode  os.FileMode
+	mtime time.Time
+}
+
+// DownloadVolume downloads the contents of a volume to a local directory,
+// preserving the volume's directory structure as well as the files' modes
+// and modification times.
+func (c *Client) DownloadVolume(
+	ctx context.Context,
+	name, dst string,
+	opts *DownloadOptions) error {
+
+	if opts == nil {
+		opts = &DownloadOptions{}
+	}
+
+	entries, err := c.volumeEntries(ctx, name, opts)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(dst, 0755); err != nil {
+		return err
+	}
+
+	var (
+		dirs  []*volumeEntry
+		files []*volumeEntry
+	)
+	for _, e := range entries {
+		if e.dir {
+			dirs = append(dirs, e)
+			if err := os.MkdirAll(e.localPath(dst), 0700); err != nil {
+				return err
+			}
+			continue
+		}
+		files = append(files, e)
+	}
+
+	if err := c.downloadFiles(ctx, name, dst, files, opts); err != nil {
+		return err
+	}
+
+	// the directories' modes and times are set last and deepest first since
+	// writing their children modifies them
+	for i := len(dirs) - 1; i >= 0; i-- {
+		if err := dirs[i].apply(dst); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (c *Client) downloadFiles(
+	ctx context.Context,
+	name, dst string,
+	files []*volumeEntry,
+	opts *DownloadOptions) error {
+
+	workers := opts.Workers
+	if workers <= 0 {
+		workers = ConcurrentHTTPConnections
+	}
+
+	var (
+		wg   = &sync.WaitGroup{}
+		once = &sync.Once{}
+		work = make(chan *volumeEntry)
+		errs error
+	)
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for e := range work {
+				if err := c.downloadFile(ctx, name, dst, e, opts); err != nil {
+					once.Do(func() {
+						errs = fmt.Errorf("download %s: %v", e.rel, err)
+						cancel()
+					})
+				}
+			}
+		}()
+	}
+
+	for _, e := range files {
+		if ctx.Err() != nil {
+			break
+		}
+		work <- e
+	}
+	close(work)
+	wg.Wait()
+
+	if errs != nil {
+		return errs
+	}
+	return ctx.Err()
+}
+
+func (c *Client) downloadFile(
+	ctx context.Context,
+	name, dst string,
+	e *volumeEntry,
+	opts *DownloadOptions) error {
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	lp := e.localPath(dst)
+	f, err := os.OpenFile(lp, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	if err := c.getVolumeFile(ctx, name, e.rel, opts.Snapshot, f); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return e.apply(dst)
+}
+
+// TarVolume writes the contents of a volume to w as a tar stream.
+func (c *Client) TarVolume(
+	ctx context.Context,
+	name string,
+	w io.Writer) error {
+
+	return c.TarVolumeWithOptions(ctx, name, w, nil)
+}
+
+// TarVolumeWithOptions writes the contents of a volume to w as a tar stream.
+// The Workers option is ignored since the stream is written sequentially.
+func (c *Client) TarVolumeWithOptions(
+	ctx context.Context,
+	name string,
+	w io.Writer,
+	opts *DownloadOptions) error {
+
+	if opts == nil {
+		opts = &DownloadOptions{}
+	}
+
+	entries, err := c.volumeEntries(ctx, name, opts)
+	if err != nil {
+		return err
+	}
+
+	tw := tar.NewWriter(w)
+	for _, e := range entries {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		hdr := &tar.Header{
+			Name:    e.rel,
+			Mode:    int64(e.mode),
+			ModTime: e.mtime,
+		}
+		if e.dir {
+			hdr.Name += "/"
+			hdr.Typeflag = tar.TypeDir
+		} else {
+			hdr.Typeflag = tar.TypeReg
+			hdr.Size = e.size
+		}
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		if e.dir {
+			continue
+		}
+		if err := c.getVolumeFile(
+			ctx, name, e.rel, opts.Snapshot, tw); err != nil {
+			return fmt.Errorf("download %s: %v", e.rel, err)
+		}
+	}
+	return tw.Close()
+}
+
+func (c *Client) getVolumeFile(
+	ctx context.Context,
+	name, rel, snapshotName string,
+	w io.Writer) error {
+
+	if snapshotName == "" {
+		return apiv2.ContainerGetFile(ctx, c.API, path.Join(name, rel), w)
+	}
+	return apiv2.SnapshotGetFile(
+		ctx, c.API, snapshotName, path.Join(name, rel), w)
+}
+
+// volumeEntries returns the filtered files and directories of a volume
+// sorted by their paths.
+func (c *Client) volumeEntries(
+	ctx context.Context,
+	name string,
+	opts *DownloadOptions) ([]*volumeEntry, error) {
+
+	children, err := c.queryVolumeTree(
+		ctx, opts.Snapshot, name, apiv2.ContainerChildrenSyncDetail)
+	if err != nil {
+		return nil, err
+	}
+
+	rels := make([]string, 0, len(children))
+	for rel := range children {
+		rels = append(rels, rel)
+	}
+	sort.Strings(rels)
+
+	var (
+		entries  []*volumeEntry
+		excluded []string
+		included = map[string]bool{}
+	)
+
+	for _, rel := range rels {
+		if isDescendantOf(rel, excluded) {
+			continue
+		}
+		child := children[rel]
+		if child.Type == nil ||
+			(*child.Type != "container" && *child.Type != "object") {
+			continue
+		}
+		if matchesAny(rel, opts.Exclude) {
+			excluded = append(excluded, rel)
+			continue
+		}
+		e := &volumeEntry{rel: rel, dir: isContainer(child), mode: 0644}
+		if e.dir {
+			e.mode = 0755
+		}
+		if child.Mode != nil {
+			e.mode = os.FileMode(*child.Mode).Perm()
+		}
+		if child.Size != nil {
+			e.size = int64(*child.Size)
+		}
+		if child.MTime != nil {
+			e.mtime = time.Unix(*child.MTime, 0)
+		}
+		if !e.dir && len(opts.Include) > 0 {
+			if !matchesAny(rel, opts.Include) {
+				continue
+			}
+			for p := path.Dir(rel); p != "."; p = path.Dir(p) {
+				included[p] = true
+			}
+		}
+		entries = append(entries, e)
+	}
+
+	if len(opts.Include) == 0 {
+		return entries, nil
+	}
+
+	// only keep the directories that contain included files
+	filtered := entries[:0]
+	for _, e := range entries {
+		if e.dir && !included[e.rel] {
+			continue
+		}
+		filtered = append(filtered, e)
+	}
+	return filtered, nil
+}
+
+func (e *volumeEntry) localPath(dst string) string {
+	return filepath.Join(dst, filepath.FromSlash(e.rel))
+}
+
+func (e *volumeEntry) apply(dst string) error {
+	lp := e.localPath(dst)
+	if err := os.Chmod(lp, e.mode); err != nil {
+		return err
+	}
+	if e.mtime.IsZero() {
+		return nil
+	}
+	return os.Chtimes(lp, e.mtime, e.mtime)
+}
+
+func matchesAny(rel string, patterns []string) bool {
+	for _, p := range patterns {
+		name := rel
+		if !strings.Contains(p, "/") {
+			name = path.Base(rel)
+		}
+		if ok, _ := path.Match(p, name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+func isDescendantOf(rel string, parents []string) bool {
+	for _, p := range parents {
+		if strings.HasPrefix(rel, p+"/") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,92 @@
+package goisilon
+
+import (
+	"archive/tar"
+	"bytes"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDownloadVolume(t *testing.T) {
+	volumeName := "test_download_volume"
+
+	srcDir, err := ioutil.TempDir("", "goisilon")
+	assertNoError(t, err)
+	defer os.RemoveAll(srcDir)
+
+	assertNoError(t, os.MkdirAll(filepath.Join(srcDir, "a"), 0755))
+	assertNoError(t, ioutil.WriteFile(
+		filepath.Join(srcDir, "a", "hello.txt"), []byte("hello"), 0640))
+	assertNoError(t, ioutil.WriteFile(
+		filepath.Join(srcDir, "a", "skip.log"), []byte("skip"), 0644))
+
+	_, err = client.CreateVolume(defaultCtx, volumeName)
+	assertNoError(t, err)
+	defer client.DeleteVolume(defaultCtx, volumeName)
+
+	_, err = client.SyncToVolume(defaultCtx, srcDir, volumeName, nil)
+	assertNoError(t, err)
+
+	dstDir, err := ioutil.TempDir("", "goisilon")
+	assertNoError(t, err)
+	defer os.RemoveAll(dstDir)
+
+	assertNoError(t, client.DownloadVolume(
+		defaultCtx, volumeName, dstDir,
+		&DownloadOptions{Exclude: []string{"*.log"}}))
+
+	buf, err := ioutil.ReadFile(filepath.Join(dstDir, "a", "hello.txt"))
+	assertNoError(t, err)
+	assert.Equal(t, "hello", string(buf))
+
+	fi, err := os.Stat(filepath.Join(dstDir, "a", "hello.txt"))
+	assertNoError(t, err)
+	assert.Equal(t, os.FileMode(0640), fi.Mode().Perm())
+
+	_, err = os.Stat(filepath.Join(dstDir, "a", "skip.log"))
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestTarVolume(t *testing.T) {
+	volumeName := "test_tar_volume"
+
+	srcDir, err := ioutil.TempDir("", "goisilon")
+	assertNoError(t, err)
+	defer os.RemoveAll(srcDir)
+
+	assertNoError(t, ioutil.WriteFile(
+		filepath.Join(srcDir, "hello.txt"), []byte("hello"), 0644))
+
+	_, err = client.CreateVolume(defaultCtx, volumeName)
+	assertNoError(t, err)
+	defer client.DeleteVolume(defaultCtx, volumeName)
+
+	_, err = client.SyncToVolume(defaultCtx, srcDir, volumeName, nil)
+	assertNoError(t, err)
+
+	w := &bytes.Buffer{}
+	assertNoError(t, client.TarVolume(defaultCtx, volumeName, w))
+
+	tr := tar.NewReader(w)
+	hdr, err := tr.Next()
+	assertNoError(t, err)
+	assert.Equal(t, "hello.txt", hdr.Name)
+	buf, err := ioutil.ReadAll(tr)
+	assertNoError(t, err)
+	assert.Equal(t, "hello", string(buf))
+
+	_, err = tr.Next()
+	assert.Equal(t, io.EOF, err)
+}
+
+func TestDownloadMatchesAny(t *testing.T) {
+	assert.True(t, matchesAny("a/b/c.log", []string{"*.log"}))
+	assert.True(t, matchesAny("a/b/c.log", []string{"a/*/c.log"}))
+	assert.False(t, matchesAny("a/b/c.log", []string{"b/*.log"}))
+	assert.False(t, matchesAny("a/b/c.txt", []string{"*.log"}))
+}
@@ -0,0 +1,34 @@
+package goisilon
+
+import "fmt"
+
+// PathError is an error that occurred while operating on a single path of a
+// volume, such as while resetting the path's ownership.
+type PathError struct {
+	Path string
+	Op   string
+	Err  error
+}
+
+// Error returns the string representation of a PathError.
+func (e *PathError) Error() string {
+	return fmt.Sprintf("%s %s: %v", e.Op, e.Path, e.Err)
+}
+
+// PathErrors is a list of errors that occurred while operating on multiple
+// paths of a volume.
+type PathErrors []*PathError
+
+// Error returns the string representation of a PathErrors.
+func (e PathErrors) Error() string {
+	if len(e) == 1 {
+		return e[0].Error()
+	}
+	return fmt.Sprintf("%s (and %d more errors)", e[0].Error(), len(e)-1)
+}
+
+// SyncError is an error that occurred while syncing a single path.
+type SyncError = PathError
+
+// SyncErrors is a list of errors that occurred during a sync.
+type SyncErrors = PathErrors
@@ -0,0 +1,265 @@
+package goisilon
+
+import (
+	"fmt"
+	"net"
+	"path"
+	"strconv"
+	"strings"
+)
+
+// ExportClientKind is the kind of an ExportClient.
+type ExportClientKind uint8
+
+const (
+	// ExportClientKindUnknown is an unknown ExportClientKind.
+	ExportClientKindUnknown ExportClientKind = iota
+
+	// ExportClientKindAddress is a single IPv4 or IPv6 address.
+	ExportClientKindAddress
+
+	// ExportClientKindNetwork is an IPv4 or IPv6 network.
+	ExportClientKindNetwork
+
+	// ExportClientKindHost is a host name.
+	ExportClientKindHost
+
+	// ExportClientKindWildcard is a host name pattern containing "*" or "?".
+	ExportClientKindWildcard
+
+	// ExportClientKindNetgroup is a netgroup.
+	ExportClientKindNetgroup
+)
+
+// ExportClient is a parsed entry of one of an export's client lists.
+type ExportClient struct {
+	kind ExportClientKind
+	ip   net.IP
+	net  *net.IPNet
+	name string
+}
+
+// ParseExportClient parses an export client entry. Entries may be IPv4 or
+// IPv6 addresses, networks in CIDR or netmask notation, host names, host
+// name patterns or netgroups prefixed with an "@". A network with a prefix
+// length of a single host, such as "10.0.0.1/32", is parsed as an address.
+func ParseExportClient(s string) (ExportClient, error) {
+	s = strings.TrimSpace(s)
+	switch {
+	case s == "":
+		return ExportClient{}, fmt.Errorf("empty client")
+	case strings.HasPrefix(s, "@"):
+		if len(s) == 1 {
+			return ExportClient{}, fmt.Errorf("empty netgroup")
+		}
+		return ExportClient{kind: ExportClientKindNetgroup, name: s[1:]}, nil
+	case strings.Contains(s, "/"):
+		n, err := parseExportClientNetwork(s)
+		if err != nil {
+			return ExportClient{}, err
+		}
+		// a network of a single host is the host's address
+		if ones, bits := n.Mask.Size(); ones == bits {
+			return ExportClient{kind: ExportClientKindAddress, ip: n.IP}, nil
+		}
+		return ExportClient{kind: ExportClientKindNetwork, net: n}, nil
+	}
+	if ip := net.ParseIP(s); ip != nil {
+		if ip4 := ip.To4(); ip4 != nil {
+			ip = ip4
+		}
+		return ExportClient{kind: ExportClientKindAddress, ip: ip}, nil
+	}
+	s = strings.ToLower(strings.TrimSuffix(s, "."))
+	kind := ExportClientKindHost
+	for _, r := range s {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9',
+			r == '-', r == '.', r == '_':
+		case r == '*', r == '?':
+			kind = ExportClientKindWildcard
+		default:
+			return ExportClient{}, fmt.Errorf("invalid host name")
+		}
+	}
+	return ExportClient{kind: kind, name: s}, nil
+}
+
+func parseExportClientNetwork(s string) (*net.IPNet, error) {
+	parts := strings.SplitN(s, "/", 2)
+	ip := net.ParseIP(parts[0])
+	if ip == nil {
+		return nil, fmt.Errorf("invalid network address")
+	}
+	bits := 8 * net.IPv6len
+	if ip4 := ip.To4(); ip4 != nil {
+		ip, bits = ip4, 8*net.IPv4len
+	}
+	ones, err := strconv.Atoi(parts[1])
+	if err != nil {
+		mask := net.ParseIP(parts[1])
+		if mask == nil || mask.To4() == nil || bits != 8*net.IPv4len {
+			return nil, fmt.Errorf("invalid netmask")
+		}
+		if ones, _ = net.IPMask(mask.To4()).Size(); ones == 0 &&
+			!mask.Equal(net.IPv4zero) {
+			return nil, fmt.Errorf("invalid netmask")
+		}
+	}
+	if ones < 0 || ones > bits {
+		return nil, fmt.Errorf("invalid prefix length")
+	}
+	mask := net.CIDRMask(ones, bits)
+	return &net.IPNet{IP: ip.Mask(mask), Mask: mask}, nil
+}
+
+// Kind returns the kind of the ExportClient.
+func (c ExportClient) Kind() ExportClientKind {
+	return c.kind
+}
+
+// String returns the canonical form of the ExportClient. Equivalent entries,
+// such as "10.0.0.1/24" and "10.0.0.0/255.255.255.0", have the same canonical
+// form.
+func (c ExportClient) String() string {
+	switch c.kind {
+	case ExportClientKindAddress:
+		return c.ip.String()
+	case ExportClientKindNetwork:
+		return c.net.String()
+	case ExportClientKindNetgroup:
+		return "@" + c.name
+	}
+	return c.name
+}
+
+// Admits returns a flag indicating whether or not the ExportClient matches
+// the provided address. Host names, host name patterns and netgroups are not
+// resolved and so never match an address.
+func (c ExportClient) Admits(addr net.IP) bool {
+	switch c.kind {
+	case ExportClientKindAddress:
+		return c.ip.Equal(addr)
+	case ExportClientKindNetwork:
+		return c.net.Contains(addr)
+	}
+	return false
+}
+
+// AdmitsHost returns a flag indicating whether or not the ExportClient
+// matches the provided host name. Only host names and host name patterns
+// match host names.
+func (c ExportClient) AdmitsHost(host string) bool {
+	host = strings.ToLower(strings.TrimSuffix(host, "."))
+	switch c.kind {
+	case ExportClientKindHost:
+		return c.name == host
+	case ExportClientKindWildcard:
+		ok, _ := path.Match(c.name, host)
+		return ok
+	}
+	return false
+}
+
+// ExportClients is a list of parsed export client entries.
+type ExportClients []ExportClient
+
+// ParseExportClients parses a list of export client entries. Entries with the
+// same canonical form are only included once, in the order in which they
+// first appear.
+func ParseExportClients(entries ...string) (ExportClients, error) {
+	var (
+		clients = ExportClients{}
+		seen    = map[string]bool{}
+	)
+	for _, s := range entries {
+		c, err := ParseExportClient(s)
+		if err != nil {
+			return nil, fmt.Errorf("invalid export client %q: %v", s, err)
+		}
+		if k := c.String(); !seen[k] {
+			seen[k] = true
+			clients = append(clients, c)
+		}
+	}
+	return clients, nil
+}
+
+// Strings returns the canonical forms of the list's entries.
+func (l ExportClients) Strings() []string {
+	s := make([]string, len(l))
+	for i, c := range l {
+		s[i] = c.String()
+	}
+	return s
+}
+
+// Admits returns a flag indicating whether or not any of the list's entries
+// match the provided address.
+func (l ExportClients) Admits(addr net.IP) bool {
+	for _, c := range l {
+		if c.Admits(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// normalizeExportClients returns the de-duplicated, canonical forms of the
+// provided client entries. Entries that cannot be parsed are kept as they
+// are and left for the cluster to accept or reject.
+func normalizeExportClients(entries ...string) []string {
+	return mergeExportClients(nil, entries)
+}
+
+// mergeExportClients appends the added client entries to an export's
+// existing entries and returns the de-duplicated, canonical result. Entries
+// that cannot be parsed are kept as they are so that they are not lost from
+// the export.
+func mergeExportClients(existing *[]string, added []string) []string {
+	var (
+		merged  = []string{}
+		seen    = map[string]bool{}
+		entries []string
+	)
+	if existing != nil {
+		entries = append(entries, *existing...)
+	}
+	for _, s := range append(entries, added...) {
+		if c, err := ParseExportClient(s); err == nil {
+			s = c.String()
+		}
+		if !seen[s] {
+			seen[s] = true
+			merged = append(merged, s)
+		}
+	}
+	return merged
+}
+
+// ExportAdmits returns a flag indicating whether or not the export admits
+// the provided address through any of its client lists. An export without
+// any clients admits every address.
+func ExportAdmits(export Export, addr net.IP) bool {
+	var (
+		any   bool
+		lists = []*[]string{
+			export.Clients,
+			export.RootClients,
+			export.ReadOnlyClients,
+			export.ReadWriteClients,
+		}
+	)
+	for _, l := range lists {
+		if l == nil {
+			continue
+		}
+		for _, s := range *l {
+			any = true
+			if c, err := ParseExportClient(s); err == nil && c.Admits(addr) {
+				return true
+			}
+		}
+	}
+	return !any
+}
@@ -0,0 +1,93 @@
+package goisilon
+
+import (
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	apiv2 "github.com/thecodeteam/goisilon/api/v2"
+)
+
+func TestParseExportClient(t *testing.T) {
+	for s, exp := range map[string]struct {
+		str  string
+		kind ExportClientKind
+	}{
+		"10.0.0.1":                 {"10.0.0.1", ExportClientKindAddress},
+		" 10.1.2.3/8 ":             {"10.0.0.0/8", ExportClientKindNetwork},
+		"192.168.1.7/255.255.0.0":  {"192.168.0.0/16", ExportClientKindNetwork},
+		"FE80:0:0::1":              {"fe80::1", ExportClientKindAddress},
+		"2001:db8::1/32":           {"2001:db8::/32", ExportClientKindNetwork},
+		"Host.Example.COM.":        {"host.example.com", ExportClientKindHost},
+		"*.example.com":            {"*.example.com", ExportClientKindWildcard},
+		"@netgroup1":               {"@netgroup1", ExportClientKindNetgroup},
+		"host_1.example.com":       {"host_1.example.com", ExportClientKindHost},
+		"10.0.0.1/32":              {"10.0.0.1", ExportClientKindAddress},
+		"10.0.0.1/255.255.255.255": {"10.0.0.1", ExportClientKindAddress},
+		"fd00::1/128":              {"fd00::1", ExportClientKindAddress},
+	} {
+		c, err := ParseExportClient(s)
+		assertNoError(t, err)
+		assert.Equal(t, exp.str, c.String(), s)
+		assert.Equal(t, exp.kind, c.Kind(), s)
+	}
+	for _, s := range []string{
+		"", "@", "10.0.0.1/33", "10.0.0.0/255.0.255.0", "bad_host!", "x/8",
+	} {
+		_, err := ParseExportClient(s)
+		assert.Error(t, err, s)
+	}
+}
+
+func TestParseExportClientsDeduplicates(t *testing.T) {
+	clients, err := ParseExportClients(
+		"10.0.0.0/24", "10.0.0.0/255.255.255.0", "HOST", "host", "10.0.0.9")
+	assertNoError(t, err)
+	assert.Equal(t, []string{"10.0.0.0/24", "host", "10.0.0.9"},
+		clients.Strings())
+
+	_, err = ParseExportClients("10.0.0.1", "bad host")
+	assert.Error(t, err)
+}
+
+func TestExportClientAdmits(t *testing.T) {
+	clients, err := ParseExportClients(
+		"10.0.0.0/24", "2001:db8::/32", "192.168.1.1", "*.example.com")
+	assertNoError(t, err)
+	assert.True(t, clients.Admits(net.ParseIP("10.0.0.200")))
+	assert.True(t, clients.Admits(net.ParseIP("2001:db8::5")))
+	assert.True(t, clients.Admits(net.ParseIP("192.168.1.1")))
+	assert.False(t, clients.Admits(net.ParseIP("10.0.1.1")))
+	assert.True(t, clients[3].AdmitsHost("web.example.com."))
+	assert.False(t, clients[3].AdmitsHost("example.org"))
+}
+
+func TestExportAdmits(t *testing.T) {
+	var (
+		clients = []string{"10.0.0.0/24"}
+		ro      = []string{"172.16.0.1"}
+	)
+	assert.True(t, ExportAdmits(&apiv2.Export{}, net.ParseIP("1.2.3.4")))
+	ex := &apiv2.Export{Clients: &clients, ReadOnlyClients: &ro}
+	assert.True(t, ExportAdmits(ex, net.ParseIP("10.0.0.7")))
+	assert.True(t, ExportAdmits(ex, net.ParseIP("172.16.0.1")))
+	assert.False(t, ExportAdmits(ex, net.ParseIP("1.2.3.4")))
+}
+
+func TestMergeExportClients(t *testing.T) {
+	existing := []string{"10.0.0.1", "weird entry"}
+	merged := mergeExportClients(
+		&existing, []string{"10.0.0.1/32", "10.0.0.0/255.0.0.0"})
+	assert.Equal(t, []string{"10.0.0.1", "weird entry", "10.0.0.0/8"}, merged)
+
+	assert.Equal(t, []string{}, mergeExportClients(nil, nil))
+}
+
+func TestNormalizeExportClients(t *testing.T) {
+	assert.Equal(t,
+		[]string{"10.0.0.1", "fd00::1", "host_1.example.com", "bad host!"},
+		normalizeExportClients(
+			"10.0.0.1", "10.0.0.1/32", "fd00::1/128", "Host_1.Example.com",
+			"host_1.example.com.", "bad host!", "bad host!"))
+}
@@ -0,0 +1,221 @@
+package goisilon
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"path"
+
+	"github.com/thecodeteam/goisilon/api"
+	apiv2 "github.com/thecodeteam/goisilon/api/v2"
+)
+
+// ExportProblem is a reason an export would be rejected by the cluster.
+type ExportProblem struct {
+
+	// ExportID is the ID of the existing export that clashes with the
+	// validated export. It is zero if the problem is not a clash.
+	ExportID int
+
+	// Path is the export path with the problem, if any.
+	Path string
+
+	// Client is the client entry with the problem, if any.
+	Client string
+
+	// Message describes the problem.
+	Message string
+}
+
+// Error returns the string representation of an ExportProblem.
+func (p *ExportProblem) Error() string {
+	msg := p.Message
+	if p.Client != "" {
+		msg = fmt.Sprintf("client %s: %s", p.Client, msg)
+	}
+	if p.Path != "" {
+		msg = fmt.Sprintf("%s: %s", p.Path, msg)
+	}
+	if p.ExportID != 0 {
+		msg = fmt.Sprintf("%s (export %d)", msg, p.ExportID)
+	}
+	return msg
+}
+
+// ExportProblems is a list of reasons an export would be rejected by the
+// cluster.
+type ExportProblems []*ExportProblem
+
+// Error returns the string representation of an ExportProblems.
+func (p ExportProblems) Error() string {
+	if len(p) == 1 {
+		return p[0].Error()
+	}
+	return fmt.Sprintf("%s (and %d more problems)", p[0].Error(), len(p)-1)
+}
+
+// ValidateExport checks an export before it is created or updated. The
+// export's client entries are parsed and compared with those of the existing
+// exports on the same paths, and the cluster's export check is consulted for
+// the clashing exports when the cluster supports it. If the export has an ID
+// then it is treated as an update of that export. All of the problems found
+// are returned as ExportProblems.
+func (c *Client) ValidateExport(
+	ctx context.Context, export *apiv2.Export) error {
+
+	var problems ExportProblems
+
+	if export.Paths == nil || len(*export.Paths) == 0 {
+		problems = append(problems, &ExportProblem{Message: "no path set"})
+		return problems
+	}
+
+	clients, bad := exportClientSet(export)
+	problems = append(problems, bad...)
+
+	clashes := map[int]bool{}
+	for _, p := range *export.Paths {
+		if !path.IsAbs(p) {
+			problems = append(problems, &ExportProblem{
+				Path: p, Message: "path is not absolute"})
+			continue
+		}
+		exports, err := c.GetExportsByPath(ctx, path.Clean(p))
+		if err != nil {
+			return err
+		}
+		for _, ex := range exports {
+			if ex.ID == export.ID || !sameExportZone(ex, export) {
+				continue
+			}
+			exClients, _ := exportClientSet(ex)
+			for _, cp := range clientClashes(clients, exClients) {
+				cp.ExportID = ex.ID
+				cp.Path = p
+				problems = append(problems, cp)
+				clashes[ex.ID] = true
+			}
+		}
+	}
+
+	checks, err := apiv2.ExportsCheck(ctx, c.API)
+	if err != nil && !isNotFound(err) {
+		return err
+	}
+	for _, ck := range checks {
+		if clashes[ck.ID] || (export.ID != 0 && ck.ID == export.ID) {
+			problems = append(problems, &ExportProblem{
+				ExportID: ck.ID, Message: ck.Message})
+		}
+	}
+
+	if len(problems) == 0 {
+		return nil
+	}
+	return problems
+}
+
+// exportClientSet returns the parsed, de-duplicated entries of all of an
+// export's client lists along with the problems with the entries that could
+// not be parsed.
+func exportClientSet(
+	export *apiv2.Export) (ExportClients, ExportProblems) {
+
+	var (
+		problems ExportProblems
+		clients  = ExportClients{}
+		seen     = map[string]bool{}
+	)
+	for _, l := range []*[]string{
+		export.Clients,
+		export.RootClients,
+		export.ReadOnlyClients,
+		export.ReadWriteClients,
+	} {
+		if l == nil {
+			continue
+		}
+		for _, s := range *l {
+			ec, err := ParseExportClient(s)
+			if err != nil {
+				problems = append(problems, &ExportProblem{
+					Client: s, Message: err.Error()})
+				continue
+			}
+			if k := ec.String(); !seen[k] {
+				seen[k] = true
+				clients = append(clients, ec)
+			}
+		}
+	}
+	return clients, problems
+}
+
+// clientClashes returns the problems caused by two exports on the same path
+// applying to the same clients. Exports without any clients apply to all
+// clients and so clash with any other export. Otherwise a problem is
+// returned for every pair of overlapping entries.
+func clientClashes(a, b ExportClients) ExportProblems {
+	switch {
+	case len(a) == 0 && len(b) == 0:
+		return ExportProblems{{
+			Message: "both exports apply to all clients"}}
+	case len(a) == 0:
+		return ExportProblems{{
+			Message: "export applies to all clients, " +
+				"including those of another export"}}
+	case len(b) == 0:
+		return ExportProblems{{
+			Message: "another export applies to all clients"}}
+	}
+	var problems ExportProblems
+	for _, ca := range a {
+		for _, cb := range b {
+			if !exportClientsOverlap(ca, cb) {
+				continue
+			}
+			msg := "client is already in another export"
+			if ca.String() != cb.String() {
+				msg = fmt.Sprintf(
+					"client overlaps %s in another export", cb)
+			}
+			problems = append(problems, &ExportProblem{
+				Client: ca.String(), Message: msg})
+		}
+	}
+	return problems
+}
+
+// exportClientsOverlap returns a flag indicating whether or not two export
+// client entries may match the same client. Addresses and networks overlap
+// when one admits the other's address or contains the other's network, and
+// host names overlap when they are equal or one is a pattern that admits
+// the other. Entries that require name resolution to compare, such as an
+// address and a host name, never overlap.
+func exportClientsOverlap(a, b ExportClient) bool {
+	if a.kind > b.kind {
+		a, b = b, a
+	}
+	switch {
+	case a.kind == ExportClientKindAddress &&
+		(b.kind == ExportClientKindAddress ||
+			b.kind == ExportClientKindNetwork):
+		return b.Admits(a.ip)
+	case a.kind == ExportClientKindNetwork &&
+		b.kind == ExportClientKindNetwork:
+		return a.net.Contains(b.net.IP) || b.net.Contains(a.net.IP)
+	case a.kind == ExportClientKindHost &&
+		b.kind == ExportClientKindWildcard:
+		return b.AdmitsHost(a.name)
+	}
+	return a.kind == b.kind && a.String() == b.String()
+}
+
+func sameExportZone(a, b *apiv2.Export) bool {
+	return a.Zone == nil || b.Zone == nil || *a.Zone == *b.Zone
+}
+
+func isNotFound(err error) bool {
+	jerr, ok := err.(*api.JSONError)
+	return ok && jerr.StatusCode == http.StatusNotFound
+}
@@ -0,0 +1,99 @@
+package goisilon
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	apiv2 "github.com/thecodeteam/goisilon/api/v2"
+)
+
+func TestValidateExportClientClashes(t *testing.T) {
+	var (
+		clients1 = []string{"10.0.0.1", "10.1.0.0/255.255.0.0"}
+		clients2 = []string{"10.1.2.3/16"}
+		ex1      = &apiv2.Export{ID: 1, Clients: &clients1}
+		ex2      = &apiv2.Export{ID: 2, RootClients: &clients2}
+	)
+	a, bad := exportClientSet(ex1)
+	assert.Len(t, bad, 0)
+	b, bad := exportClientSet(ex2)
+	assert.Len(t, bad, 0)
+
+	problems := clientClashes(a, b)
+	if !assert.Len(t, problems, 1) {
+		t.FailNow()
+	}
+	assert.Equal(t, "10.1.0.0/16", problems[0].Client)
+
+	assert.Len(t, clientClashes(ExportClients{}, ExportClients{}), 1)
+}
+
+func TestValidateExportClientClashesAllClients(t *testing.T) {
+	clients := []string{"10.0.0.1"}
+	a, _ := exportClientSet(&apiv2.Export{ID: 1, Clients: &clients})
+
+	// a new export with clients on the path of an all-clients export
+	problems := clientClashes(a, ExportClients{})
+	if assert.Len(t, problems, 1) {
+		assert.Contains(t, problems[0].Message, "another export")
+	}
+
+	// a new all-clients export on the path of an export with clients
+	problems = clientClashes(ExportClients{}, a)
+	if assert.Len(t, problems, 1) {
+		assert.Contains(t, problems[0].Message, "all clients")
+	}
+}
+
+func TestValidateExportClientOverlaps(t *testing.T) {
+	for _, tt := range []struct {
+		a, b    string
+		overlap bool
+	}{
+		{"10.0.0.0/24", "10.0.0.5", true},
+		{"10.0.0.5", "10.0.0.0/24", true},
+		{"10.0.0.1/32", "10.0.0.1", true},
+		{"10.0.0.0/16", "10.0.1.0/24", true},
+		{"10.0.1.0/24", "10.0.0.0/16", true},
+		{"10.0.0.0/24", "10.0.1.0/24", false},
+		{"10.0.0.0/24", "10.0.1.5", false},
+		{"*.example.com", "host1.example.com", true},
+		{"host1.example.com", "host?.example.com", true},
+		{"*.example.com", "host1.example.org", false},
+		{"host1.example.com", "HOST1.example.com.", true},
+		{"@ng1", "@ng1", true},
+		{"@ng1", "@ng2", false},
+		{"10.0.0.1", "host1.example.com", false},
+		{"fd00::/64", "fd00::1", true},
+	} {
+		a, err := ParseExportClient(tt.a)
+		assertNoError(t, err)
+		b, err := ParseExportClient(tt.b)
+		assertNoError(t, err)
+		assert.Equal(
+			t, tt.overlap, exportClientsOverlap(a, b), "%s %s", tt.a, tt.b)
+	}
+}
+
+func TestValidateExportClientClashesOverlaps(t *testing.T) {
+	var (
+		clients1 = []string{"10.0.0.0/24", "*.example.com", "10.2.0.1/32"}
+		clients2 = []string{"10.0.0.5", "host1.example.com", "10.2.0.1"}
+		ex1      = &apiv2.Export{ID: 1, Clients: &clients1}
+		ex2      = &apiv2.Export{ID: 2, ReadOnlyClients: &clients2}
+	)
+	a, _ := exportClientSet(ex1)
+	b, _ := exportClientSet(ex2)
+
+	problems := clientClashes(a, b)
+	if !assert.Len(t, problems, 3) {
+		t.FailNow()
+	}
+	assert.Equal(t, "10.0.0.0/24", problems[0].Client)
+	assert.Contains(t, problems[0].Message, "10.0.0.5")
+	assert.Equal(t, "*.example.com", problems[1].Client)
+	assert.Contains(t, problems[1].Message, "host1.example.com")
+	assert.Equal(t, "10.2.0.1", problems[2].Client)
+	assert.Contains(t, problems[2].Message, "already")
+}
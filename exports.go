@@ -2,6 +2,7 @@ package goisilon
 
 import (
 	"context"
+	"path"
 
 	api "github.com/thecodeteam/goisilon/api/v2"
 )
@@ -20,24 +21,27 @@ func (c *Client) GetExportByID(ctx context.Context, id int) (Export, error) {
 	return api.ExportInspect(ctx, c.API, id)
 }
 
+// GetExportsByPath returns all of the exports that include the provided
+// path.
+func (c *Client) GetExportsByPath(
+	ctx context.Context, p string) (ExportList, error) {
+
+	return api.ExportsListByPath(ctx, c.API, p)
+}
+
 // GetExportByName returns the first export with a path for the provided
 // volume name.
 func (c *Client) GetExportByName(
 	ctx context.Context, name string) (Export, error) {
 
-	exports, err := api.ExportsList(ctx, c.API)
+	exports, err := c.GetExportsByPath(ctx, c.API.VolumePath(name))
 	if err != nil {
 		return nil, err
 	}
-	path := c.API.VolumePath(name)
-	for _, ex := range exports {
-		for _, p := range *ex.Paths {
-			if p == path {
-				return ex, nil
-			}
-		}
+	if len(exports) == 0 {
+		return nil, nil
 	}
-	return nil, nil
+	return exports[0], nil
 }
 
 // Export the volume with a given name on the cluster
@@ -519,14 +523,9 @@ func (c *Client) AddExportClients(
 	if ex == nil {
 		return nil
 	}
-	addClients := ex.Clients
-	if addClients == nil {
-		addClients = &clients
-	} else {
-		*addClients = append(*addClients, clients...)
-	}
+	addClients := mergeExportClients(ex.Clients, clients)
 	return api.ExportUpdate(
-		ctx, c.API, &api.Export{ID: ex.ID, Clients: addClients})
+		ctx, c.API, &api.Export{ID: ex.ID, Clients: &addClients})
 }
 
 // AddExportClientsByID adds to the Export's clients property.
@@ -540,20 +539,17 @@ func (c *Client) AddExportClientsByID(
 	if ex == nil {
 		return nil
 	}
-	addClients := ex.Clients
-	if addClients == nil {
-		addClients = &clients
-	} else {
-		*addClients = append(*addClients, clients...)
-	}
+	addClients := mergeExportClients(ex.Clients, clients)
 	return api.ExportUpdate(
-		ctx, c.API, &api.Export{ID: ex.ID, Clients: addClients})
+		ctx, c.API, &api.Export{ID: ex.ID, Clients: &addClients})
 }
 
 // SetExportClients sets the Export's clients property.
 func (c *Client) SetExportClients(
 	ctx context.Context, name string, clients ...string) error {
 
+	clients = normalizeExportClients(clients...)
+
 	ok, id, err := c.IsExported(ctx, name)
 	if err != nil {
 		return err
@@ -568,6 +564,8 @@ func (c *Client) SetExportClients(
 func (c *Client) SetExportClientsByID(
 	ctx context.Context, id int, clients ...string) error {
 
+	clients = normalizeExportClients(clients...)
+
 	return api.ExportUpdate(ctx, c.API, &api.Export{ID: id, Clients: &clients})
 }
 
@@ -630,14 +628,9 @@ func (c *Client) AddExportRootClients(
 	if ex == nil {
 		return nil
 	}
-	addClients := ex.RootClients
-	if addClients == nil {
-		addClients = &clients
-	} else {
-		*addClients = append(*addClients, clients...)
-	}
+	addClients := mergeExportClients(ex.RootClients, clients)
 	return api.ExportUpdate(
-		ctx, c.API, &api.Export{ID: ex.ID, RootClients: addClients})
+		ctx, c.API, &api.Export{ID: ex.ID, RootClients: &addClients})
 }
 
 // AddExportRootClientsByID adds to the Export's root_clients property.
@@ -651,20 +644,17 @@ func (c *Client) AddExportRootClientsByID(
 	if ex == nil {
 		return nil
 	}
-	addClients := ex.RootClients
-	if addClients == nil {
-		addClients = &clients
-	} else {
-		*addClients = append(*addClients, clients...)
-	}
+	addClients := mergeExportClients(ex.RootClients, clients)
 	return api.ExportUpdate(
-		ctx, c.API, &api.Export{ID: ex.ID, RootClients: addClients})
+		ctx, c.API, &api.Export{ID: ex.ID, RootClients: &addClients})
 }
 
 // SetExportRootClients sets the Export's root_clients property.
 func (c *Client) SetExportRootClients(
 	ctx context.Context, name string, clients ...string) error {
 
+	clients = normalizeExportClients(clients...)
+
 	ok, id, err := c.IsExported(ctx, name)
 	if err != nil {
 		return err
@@ -680,6 +670,8 @@ func (c *Client) SetExportRootClients(
 func (c *Client) SetExportRootClientsByID(
 	ctx context.Context, id int, clients ...string) error {
 
+	clients = normalizeExportClients(clients...)
+
 	return api.ExportUpdate(
 		ctx, c.API, &api.Export{ID: id, RootClients: &clients})
 }
@@ -698,6 +690,183 @@ func (c *Client) ClearExportRootClientsByID(
 	return c.SetExportRootClientsByID(ctx, id, []string{}...)
 }
 
+// updateExport applies the provided function to a new Export that has the
+// same ID as the Export for the provided volume name and PUTs the result.
+func (c *Client) updateExport(
+	ctx context.Context, name string, update func(Export)) error {
+
+	ex, err := c.GetExportByName(ctx, name)
+	if err != nil {
+		return err
+	}
+	if ex == nil {
+		return nil
+	}
+	return c.updateExportByID(ctx, ex.ID, update)
+}
+
+// updateExportByID applies the provided function to a new Export with the
+// provided ID and PUTs the result.
+func (c *Client) updateExportByID(
+	ctx context.Context, id int, update func(Export)) error {
+
+	nex := &api.Export{ID: id}
+	update(nex)
+	return api.ExportUpdate(ctx, c.API, nex)
+}
+
+// SetExportReadOnly sets the Export's read_only property.
+func (c *Client) SetExportReadOnly(
+	ctx context.Context, name string, readOnly bool) error {
+
+	return c.updateExport(ctx, name, func(e Export) { e.ReadOnly = &readOnly })
+}
+
+// SetExportReadOnlyByID sets the Export's read_only property.
+func (c *Client) SetExportReadOnlyByID(
+	ctx context.Context, id int, readOnly bool) error {
+
+	return c.updateExportByID(
+		ctx, id, func(e Export) { e.ReadOnly = &readOnly })
+}
+
+// SetExportSecurityFlavors sets the Export's security_flavors property.
+func (c *Client) SetExportSecurityFlavors(
+	ctx context.Context, name string, flavors ...api.SecurityFlavor) error {
+
+	return c.updateExport(
+		ctx, name, func(e Export) { e.SecurityFlavors = &flavors })
+}
+
+// SetExportSecurityFlavorsByID sets the Export's security_flavors property.
+func (c *Client) SetExportSecurityFlavorsByID(
+	ctx context.Context, id int, flavors ...api.SecurityFlavor) error {
+
+	return c.updateExportByID(
+		ctx, id, func(e Export) { e.SecurityFlavors = &flavors })
+}
+
+// SetExportDescription sets the Export's description property.
+func (c *Client) SetExportDescription(
+	ctx context.Context, name, description string) error {
+
+	return c.updateExport(
+		ctx, name, func(e Export) { e.Description = &description })
+}
+
+// SetExportDescriptionByID sets the Export's description property.
+func (c *Client) SetExportDescriptionByID(
+	ctx context.Context, id int, description string) error {
+
+	return c.updateExportByID(
+		ctx, id, func(e Export) { e.Description = &description })
+}
+
+// SetExportAllDirs sets the Export's all_dirs property.
+func (c *Client) SetExportAllDirs(
+	ctx context.Context, name string, allDirs bool) error {
+
+	return c.updateExport(ctx, name, func(e Export) { e.AllDirs = &allDirs })
+}
+
+// SetExportAllDirsByID sets the Export's all_dirs property.
+func (c *Client) SetExportAllDirsByID(
+	ctx context.Context, id int, allDirs bool) error {
+
+	return c.updateExportByID(
+		ctx, id, func(e Export) { e.AllDirs = &allDirs })
+}
+
+// SetExportMapLookupUID sets the Export's map_lookup_uid property.
+func (c *Client) SetExportMapLookupUID(
+	ctx context.Context, name string, lookup bool) error {
+
+	return c.updateExport(
+		ctx, name, func(e Export) { e.MapLookupUID = &lookup })
+}
+
+// SetExportMapLookupUIDByID sets the Export's map_lookup_uid property.
+func (c *Client) SetExportMapLookupUIDByID(
+	ctx context.Context, id int, lookup bool) error {
+
+	return c.updateExportByID(
+		ctx, id, func(e Export) { e.MapLookupUID = &lookup })
+}
+
+// SetExportCommitAsynchronous sets the Export's commit_asynchronous
+// property.
+func (c *Client) SetExportCommitAsynchronous(
+	ctx context.Context, name string, async bool) error {
+
+	return c.updateExport(
+		ctx, name, func(e Export) { e.CommitAsynchronous = &async })
+}
+
+// SetExportCommitAsynchronousByID sets the Export's commit_asynchronous
+// property.
+func (c *Client) SetExportCommitAsynchronousByID(
+	ctx context.Context, id int, async bool) error {
+
+	return c.updateExportByID(
+		ctx, id, func(e Export) { e.CommitAsynchronous = &async })
+}
+
+// SetExportReadOnlyClients sets the Export's read_only_clients property.
+func (c *Client) SetExportReadOnlyClients(
+	ctx context.Context, name string, clients ...string) error {
+
+	clients = normalizeExportClients(clients...)
+
+	return c.updateExport(
+		ctx, name, func(e Export) { e.ReadOnlyClients = &clients })
+}
+
+// SetExportReadOnlyClientsByID sets the Export's read_only_clients property.
+func (c *Client) SetExportReadOnlyClientsByID(
+	ctx context.Context, id int, clients ...string) error {
+
+	clients = normalizeExportClients(clients...)
+
+	return c.updateExportByID(
+		ctx, id, func(e Export) { e.ReadOnlyClients = &clients })
+}
+
+// SetExportReadWriteClients sets the Export's read_write_clients property.
+func (c *Client) SetExportReadWriteClients(
+	ctx context.Context, name string, clients ...string) error {
+
+	clients = normalizeExportClients(clients...)
+
+	return c.updateExport(
+		ctx, name, func(e Export) { e.ReadWriteClients = &clients })
+}
+
+// SetExportReadWriteClientsByID sets the Export's read_write_clients
+// property.
+func (c *Client) SetExportReadWriteClientsByID(
+	ctx context.Context, id int, clients ...string) error {
+
+	clients = normalizeExportClients(clients...)
+
+	return c.updateExportByID(
+		ctx, id, func(e Export) { e.ReadWriteClients = &clients })
+}
+
+// SetExportBlockSize sets the Export's block_size property.
+func (c *Client) SetExportBlockSize(
+	ctx context.Context, name string, size int) error {
+
+	return c.updateExport(ctx, name, func(e Export) { e.BlockSize = &size })
+}
+
+// SetExportBlockSizeByID sets the Export's block_size property.
+func (c *Client) SetExportBlockSizeByID(
+	ctx context.Context, id int, size int) error {
+
+	return c.updateExportByID(
+		ctx, id, func(e Export) { e.BlockSize = &size })
+}
+
 // Stop exporting a given volume from the cluster
 func (c *Client) Unexport(
 	ctx context.Context, name string) error {
@@ -733,3 +902,48 @@ func (c *Client) IsExported(
 	}
 	return true, export.ID, nil
 }
+
+// exportIndex relates paths to the Exports that include them, allowing the
+// Exports that cover a path to be found without scanning every Export.
+type exportIndex map[string][]Export
+
+// newExportIndex builds an exportIndex from a list of Exports.
+func newExportIndex(exports ExportList) exportIndex {
+	idx := exportIndex{}
+	for _, ex := range exports {
+		if ex.Paths == nil {
+			continue
+		}
+		for _, p := range *ex.Paths {
+			p = path.Clean(p)
+			idx[p] = append(idx[p], ex)
+		}
+	}
+	return idx
+}
+
+// covering returns the Exports that cover the provided path. These are the
+// Exports with the path itself as well as the Exports of the path's parents
+// that have the "all_dirs" property enabled. Each Export is returned at most
+// once, ordered from the nearest path to the furthest.
+func (idx exportIndex) covering(p string) []Export {
+	var (
+		exports []Export
+		seen    = map[int]bool{}
+	)
+	for p, exact := path.Clean(p), true; ; p, exact = path.Dir(p), false {
+		for _, ex := range idx[p] {
+			if seen[ex.ID] {
+				continue
+			}
+			if !exact && (ex.AllDirs == nil || !*ex.AllDirs) {
+				continue
+			}
+			seen[ex.ID] = true
+			exports = append(exports, ex)
+		}
+		if p == "/" || p == "." {
+			return exports
+		}
+	}
+}
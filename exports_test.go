@@ -6,6 +6,8 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+
+	apiv2 "github.com/thecodeteam/goisilon/api/v2"
 )
 
 func TestExportsList(t *testing.T) {
@@ -563,3 +565,67 @@ func testExportClientsClear(
 
 	assert.Len(t, getClients(defaultCtx, export), 0)
 }
+
+func TestExportSettingsSet(t *testing.T) {
+	volumeName := "test_set_export_settings"
+
+	// initialize the export
+	_, err := client.CreateVolume(defaultCtx, volumeName)
+	assertNoError(t, err)
+
+	exportID, err := client.Export(defaultCtx, volumeName)
+	assertNoError(t, err)
+
+	// make sure we clean up when we're done
+	defer client.UnexportByID(defaultCtx, exportID)
+	defer client.DeleteVolume(defaultCtx, volumeName)
+
+	assertNoError(t, client.SetExportReadOnly(defaultCtx, volumeName, true))
+	assertNoError(t, client.SetExportSecurityFlavors(
+		defaultCtx, volumeName,
+		apiv2.SecurityFlavorUnix, apiv2.SecurityFlavorKerberos5))
+	assertNoError(t, client.SetExportDescriptionByID(
+		defaultCtx, exportID, volumeName))
+
+	export, err := client.GetExportByID(defaultCtx, exportID)
+	assertNoError(t, err)
+	assertNotNil(t, export)
+
+	assert.True(t, *export.ReadOnly)
+	assert.Equal(t, volumeName, *export.Description)
+	assert.Equal(
+		t,
+		[]apiv2.SecurityFlavor{
+			apiv2.SecurityFlavorUnix, apiv2.SecurityFlavorKerberos5,
+		},
+		*export.SecurityFlavors)
+}
+
+func TestExportIndexCovering(t *testing.T) {
+	var (
+		allDirs = true
+		paths1  = []string{"/ifs/volumes/a"}
+		paths2  = []string{"/ifs/volumes", "/ifs/other"}
+		paths3  = []string{"/ifs/volumes/"}
+		ex1     = &apiv2.Export{ID: 1, Paths: &paths1}
+		ex2     = &apiv2.Export{ID: 2, Paths: &paths2, AllDirs: &allDirs}
+		ex3     = &apiv2.Export{ID: 3, Paths: &paths3}
+		idx     = newExportIndex(ExportList{ex1, ex2, ex3})
+	)
+
+	exports := idx.covering("/ifs/volumes/a")
+	if !assert.Len(t, exports, 2) {
+		t.FailNow()
+	}
+	assert.Equal(t, 1, exports[0].ID)
+	assert.Equal(t, 2, exports[1].ID)
+
+	exports = idx.covering("/ifs/volumes")
+	if !assert.Len(t, exports, 2) {
+		t.FailNow()
+	}
+	assert.Equal(t, 2, exports[0].ID)
+	assert.Equal(t, 3, exports[1].ID)
+
+	assert.Len(t, idx.covering("/ifs/unexported"), 0)
+}
@@ -3,13 +3,18 @@ package goisilon
 import (
 	"context"
 	"flag"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"strings"
 	"testing"
 
 	"github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
 	log "github.com/akutz/gournal"
 	glogrus "github.com/akutz/gournal/logrus"
+
+	"github.com/thecodeteam/goisilon/api"
 )
 
 var (
@@ -74,3 +79,27 @@ func assertNotNil(t *testing.T, i interface{}) {
 		t.FailNow()
 	}
 }
+
+// newTestClient returns a client of a local server that answers the API
+// version request itself and passes every other request to the provided
+// handler with the trailing slash trimmed from its path. The server is
+// closed when the test completes.
+func newTestClient(
+	t *testing.T, h http.HandlerFunc, opts *api.ClientOptions) *Client {
+
+	srv := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			r.URL.Path = strings.TrimSuffix(r.URL.Path, "/")
+			if r.URL.Path == "/platform/latest" {
+				w.Write([]byte(`{"latest":"3"}`))
+				return
+			}
+			h(w, r)
+		}))
+	t.Cleanup(srv.Close)
+
+	c, err := NewClientWithOptions(
+		defaultCtx, srv.URL, "test", "", "test", opts)
+	assertNoError(t, err)
+	return c
+}
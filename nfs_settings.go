@@ -0,0 +1,218 @@
+package goisilon
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+
+	api "github.com/thecodeteam/goisilon/api/v2"
+)
+
+type NFSGlobalSettings *api.NFSGlobalSettings
+type NFSExportSettings *api.NFSExportSettings
+type NFSZoneSettings *api.NFSZoneSettings
+
+// SettingChange is a setting that would be modified by an update.
+type SettingChange struct {
+
+	// Name is the name of the setting as it appears in the API.
+	Name string
+
+	// Current is the setting's current value, or nil if it is not set.
+	Current interface{}
+
+	// Proposed is the setting's value after the update.
+	Proposed interface{}
+}
+
+// String returns the string representation of a SettingChange.
+func (c *SettingChange) String() string {
+	return fmt.Sprintf(
+		"%s: %s -> %s",
+		c.Name, settingString(c.Current), settingString(c.Proposed))
+}
+
+func settingString(v interface{}) string {
+	if v == nil {
+		return "<unset>"
+	}
+	buf, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprintf("%v", v)
+	}
+	return string(buf)
+}
+
+// SettingChanges is a list of settings that would be modified by an update.
+type SettingChanges []*SettingChange
+
+// String returns the string representation of a SettingChanges, one change
+// per line.
+func (c SettingChanges) String() string {
+	lines := make([]string, len(c))
+	for i, sc := range c {
+		lines[i] = sc.String()
+	}
+	return strings.Join(lines, "\n")
+}
+
+// GetNFSGlobalSettings returns the cluster's global NFS settings.
+func (c *Client) GetNFSGlobalSettings(
+	ctx context.Context) (NFSGlobalSettings, error) {
+
+	return api.NFSGlobalSettingsGet(ctx, c.API)
+}
+
+// PreviewNFSGlobalSettings returns the changes that updating the cluster's
+// global NFS settings with the provided settings would make.
+func (c *Client) PreviewNFSGlobalSettings(
+	ctx context.Context,
+	settings *api.NFSGlobalSettings) (SettingChanges, error) {
+
+	current, err := api.NFSGlobalSettingsGet(ctx, c.API)
+	if err != nil {
+		return nil, err
+	}
+	return diffSettings(current, settings), nil
+}
+
+// UpdateNFSGlobalSettings updates the cluster's global NFS settings with the
+// non-nil fields of the provided settings and returns the changes that were
+// made. The settings are only sent to the cluster if they would change.
+func (c *Client) UpdateNFSGlobalSettings(
+	ctx context.Context,
+	settings *api.NFSGlobalSettings) (SettingChanges, error) {
+
+	changes, err := c.PreviewNFSGlobalSettings(ctx, settings)
+	if err != nil || len(changes) == 0 {
+		return nil, err
+	}
+	if err := api.NFSGlobalSettingsUpdate(ctx, c.API, settings); err != nil {
+		return nil, err
+	}
+	return changes, nil
+}
+
+// GetNFSExportSettings returns the default NFS export settings of an access
+// zone. If zone is empty then the System zone is used.
+func (c *Client) GetNFSExportSettings(
+	ctx context.Context, zone string) (NFSExportSettings, error) {
+
+	return api.NFSExportSettingsGet(ctx, c.API, zone)
+}
+
+// PreviewNFSExportSettings returns the changes that updating the default NFS
+// export settings of an access zone with the provided settings would make.
+func (c *Client) PreviewNFSExportSettings(
+	ctx context.Context,
+	zone string,
+	settings *api.NFSExportSettings) (SettingChanges, error) {
+
+	current, err := api.NFSExportSettingsGet(ctx, c.API, zone)
+	if err != nil {
+		return nil, err
+	}
+	return diffSettings(current, settings), nil
+}
+
+// UpdateNFSExportSettings updates the default NFS export settings of an
+// access zone with the non-nil fields of the provided settings and returns
+// the changes that were made.
+func (c *Client) UpdateNFSExportSettings(
+	ctx context.Context,
+	zone string,
+	settings *api.NFSExportSettings) (SettingChanges, error) {
+
+	changes, err := c.PreviewNFSExportSettings(ctx, zone, settings)
+	if err != nil || len(changes) == 0 {
+		return nil, err
+	}
+	if err := api.NFSExportSettingsUpdate(
+		ctx, c.API, zone, settings); err != nil {
+		return nil, err
+	}
+	return changes, nil
+}
+
+// GetNFSZoneSettings returns the NFS settings of an access zone. If zone is
+// empty then the System zone is used.
+func (c *Client) GetNFSZoneSettings(
+	ctx context.Context, zone string) (NFSZoneSettings, error) {
+
+	return api.NFSZoneSettingsGet(ctx, c.API, zone)
+}
+
+// PreviewNFSZoneSettings returns the changes that updating the NFS settings
+// of an access zone with the provided settings would make.
+func (c *Client) PreviewNFSZoneSettings(
+	ctx context.Context,
+	zone string,
+	settings *api.NFSZoneSettings) (SettingChanges, error) {
+
+	current, err := api.NFSZoneSettingsGet(ctx, c.API, zone)
+	if err != nil {
+		return nil, err
+	}
+	return diffSettings(current, settings), nil
+}
+
+// UpdateNFSZoneSettings updates the NFS settings of an access zone with the
+// non-nil fields of the provided settings and returns the changes that were
+// made.
+func (c *Client) UpdateNFSZoneSettings(
+	ctx context.Context,
+	zone string,
+	settings *api.NFSZoneSettings) (SettingChanges, error) {
+
+	changes, err := c.PreviewNFSZoneSettings(ctx, zone, settings)
+	if err != nil || len(changes) == 0 {
+		return nil, err
+	}
+	if err := api.NFSZoneSettingsUpdate(
+		ctx, c.API, zone, settings); err != nil {
+		return nil, err
+	}
+	return changes, nil
+}
+
+// diffSettings compares the non-nil pointer fields of the proposed settings
+// with the same fields of the current settings. Both arguments must be
+// pointers to the same struct type.
+func diffSettings(current, proposed interface{}) SettingChanges {
+	var (
+		changes SettingChanges
+		pv      = reflect.ValueOf(proposed).Elem()
+		cv      = reflect.ValueOf(current)
+	)
+	if cv.IsNil() {
+		cv = reflect.New(pv.Type())
+	}
+	cv = cv.Elem()
+
+	for i := 0; i < pv.NumField(); i++ {
+		pf := pv.Field(i)
+		if pf.Kind() != reflect.Ptr || pf.IsNil() {
+			continue
+		}
+		var (
+			cf       = cv.Field(i)
+			proposed = pf.Elem().Interface()
+			current  interface{}
+		)
+		if !cf.IsNil() {
+			current = cf.Elem().Interface()
+		}
+		if current != nil && reflect.DeepEqual(current, proposed) {
+			continue
+		}
+		name := strings.SplitN(pv.Type().Field(i).Tag.Get("json"), ",", 2)[0]
+		changes = append(changes, &SettingChange{
+			Name:     name,
+			Current:  current,
+			Proposed: proposed,
+		})
+	}
+	return changes
+}
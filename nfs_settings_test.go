@@ -0,0 +1,54 @@
+package goisilon
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	apiv2 "github.com/thecodeteam/goisilon/api/v2"
+)
+
+func TestNFSGlobalSettingsPreview(t *testing.T) {
+	settings, err := client.GetNFSGlobalSettings(defaultCtx)
+	assertNoError(t, err)
+	assertNotNil(t, settings)
+
+	// proposing the current settings results in no changes
+	changes, err := client.PreviewNFSGlobalSettings(
+		defaultCtx,
+		&apiv2.NFSGlobalSettings{NFSv3Enabled: settings.NFSv3Enabled})
+	assertNoError(t, err)
+	assert.Len(t, changes, 0)
+}
+
+func TestDiffSettings(t *testing.T) {
+	var (
+		yes     = true
+		no      = false
+		threads = 16
+		krb5    = []apiv2.SecurityFlavor{apiv2.SecurityFlavorKerberos5}
+		unix    = []apiv2.SecurityFlavor{apiv2.SecurityFlavorUnix}
+	)
+
+	changes := diffSettings(
+		&apiv2.NFSGlobalSettings{NFSv3Enabled: &yes, NFSv4Enabled: &no},
+		&apiv2.NFSGlobalSettings{
+			NFSv3Enabled: &yes, NFSv4Enabled: &yes, RPCMaxThreads: &threads})
+	if !assert.Len(t, changes, 2) {
+		t.FailNow()
+	}
+	assert.Equal(t, "nfsv4_enabled: false -> true", changes[0].String())
+	assert.Equal(t, "rpc_maxthreads: <unset> -> 16", changes[1].String())
+
+	changes = diffSettings(
+		&apiv2.NFSExportSettings{SecurityFlavors: &unix},
+		&apiv2.NFSExportSettings{SecurityFlavors: &krb5})
+	if !assert.Len(t, changes, 1) {
+		t.FailNow()
+	}
+	assert.Equal(t, `security_flavors: ["unix"] -> ["krb5"]`, changes[0].String())
+
+	var nilSettings *apiv2.NFSZoneSettings
+	assert.Len(t, diffSettings(nilSettings, &apiv2.NFSZoneSettings{
+		NFSv4NoNames: &no}), 1)
+}
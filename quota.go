@@ -2,8 +2,13 @@ package goisilon
 
 import (
 	"context"
+	"errors"
+	"path"
+	"strings"
+	"time"
 
 	api "github.com/thecodeteam/goisilon/api/v1"
+	apiv2 "github.com/thecodeteam/goisilon/api/v2"
 )
 
 type Quota *api.IsiQuota
@@ -18,8 +23,6 @@ func (c *Client) GetQuota(ctx context.Context, name string) (Quota, error) {
 	return quota, nil
 }
 
-// TODO: Add a means to set/update more fields of the quota
-
 // SetQuota sets the max size (hard threshold) of a quota for a volume
 func (c *Client) SetQuotaSize(
 	ctx context.Context, name string, size int64) error {
@@ -40,3 +43,400 @@ func (c *Client) UpdateQuotaSize(
 func (c *Client) ClearQuota(ctx context.Context, name string) error {
 	return api.DeleteIsiQuota(ctx, c.API, c.API.VolumePath(name))
 }
+
+// QuotaSpec is the desired configuration of a volume's directory quota.
+type QuotaSpec struct {
+
+	// Hard is the hard threshold in bytes. Writes that would exceed it are
+	// denied. If zero, the quota has no hard threshold.
+	Hard int64
+
+	// Soft is the soft threshold in bytes. Writes are denied once the quota
+	// has exceeded it for longer than SoftGrace. If zero, the quota has no
+	// soft threshold.
+	Soft int64
+
+	// SoftGrace is how long the soft threshold may be exceeded. It is
+	// required if Soft is set and is truncated to seconds.
+	SoftGrace time.Duration
+
+	// Advisory is the advisory threshold in bytes. Exceeding it only
+	// triggers notifications. If zero, the quota has no advisory threshold.
+	Advisory int64
+
+	// IncludeSnapshots indicates whether or not snapshot data counts
+	// toward the quota. It can only be set when the quota is created.
+	IncludeSnapshots bool
+
+	// ThresholdsIncludeOverhead indicates whether or not the thresholds
+	// apply to the data's physical size, including protection overhead,
+	// rather than its logical size.
+	ThresholdsIncludeOverhead bool
+
+	// Container indicates whether or not the hard threshold is reported to
+	// clients as the size of the file system, for example as the NFS free
+	// space.
+	Container bool
+
+	// AccountingOnly indicates whether or not the quota only accounts for
+	// usage without enforcing its thresholds. Quotas are enforced by
+	// default.
+	AccountingOnly bool
+}
+
+// Validate returns an error if the QuotaSpec cannot be applied.
+func (s *QuotaSpec) Validate() error {
+	if s.Hard < 0 || s.Soft < 0 || s.Advisory < 0 {
+		return errors.New("quota thresholds cannot be negative")
+	}
+	if s.Soft > 0 && s.SoftGrace < time.Second {
+		return errors.New("quota soft threshold requires a soft grace period")
+	}
+	if s.Soft == 0 && s.SoftGrace != 0 {
+		return errors.New("quota soft grace period requires a soft threshold")
+	}
+	if s.Hard > 0 && s.Soft > s.Hard {
+		return errors.New("quota soft threshold exceeds hard threshold")
+	}
+	if s.Advisory > 0 &&
+		((s.Soft > 0 && s.Advisory > s.Soft) ||
+			(s.Hard > 0 && s.Advisory > s.Hard)) {
+		return errors.New("quota advisory threshold exceeds other thresholds")
+	}
+	return nil
+}
+
+func (s *QuotaSpec) thresholds() *apiv2.QuotaThresholds {
+	t := &apiv2.QuotaThresholds{}
+	if s.Hard > 0 {
+		t.Hard = &s.Hard
+	}
+	if s.Soft > 0 {
+		t.Soft = &s.Soft
+		grace := int(s.SoftGrace / time.Second)
+		t.SoftGrace = &grace
+	}
+	if s.Advisory > 0 {
+		t.Advisory = &s.Advisory
+	}
+	return t
+}
+
+// quotaSpecFromQuota returns the QuotaSpec that describes an existing quota.
+func quotaSpecFromQuota(q *apiv2.Quota) *QuotaSpec {
+	s := &QuotaSpec{
+		IncludeSnapshots:          boolValue(q.IncludeSnapshots),
+		ThresholdsIncludeOverhead: boolValue(q.ThresholdsIncludeOverhead),
+		Container:                 boolValue(q.Container),
+		AccountingOnly:            !boolValue(q.Enforced),
+	}
+	if t := q.Thresholds; t != nil {
+		s.Hard = int64Value(t.Hard)
+		s.Soft = int64Value(t.Soft)
+		s.Advisory = int64Value(t.Advisory)
+		if s.Soft > 0 && t.SoftGrace != nil {
+			s.SoftGrace = time.Duration(*t.SoftGrace) * time.Second
+		}
+	}
+	return s
+}
+
+func boolValue(b *bool) bool {
+	return b != nil && *b
+}
+
+func int64Value(i *int64) int64 {
+	if i == nil {
+		return 0
+	}
+	return *i
+}
+
+// findQuota returns the quota of the provided type and persona for the
+// provided path, or nil if there is none. The persona is ignored for
+// directory and default quotas.
+func (c *Client) findQuota(
+	ctx context.Context,
+	p string,
+	quotaType apiv2.QuotaType,
+	persona *apiv2.Persona) (*apiv2.Quota, error) {
+
+	if !isPersonaQuotaType(quotaType) {
+		persona = nil
+	}
+	quotas, err := apiv2.QuotasListWithFilter(
+		ctx, c.API, &apiv2.QuotaFilter{
+			Type:    &quotaType,
+			Persona: persona,
+			Path:    &p,
+		})
+	if err != nil {
+		return nil, err
+	}
+	for _, q := range quotas {
+		if !quotaPathIs(q, p) || q.Type == nil || *q.Type != quotaType {
+			continue
+		}
+		if persona != nil && !personaMatches(persona, q.Persona) {
+			continue
+		}
+		return q, nil
+	}
+	return nil, nil
+}
+
+// quotaPathIs returns a flag indicating whether or not a quota's path is the
+// provided path. The paths are compared in their cleaned forms so that the
+// quotas of every volume root match regardless of how the root was written.
+func quotaPathIs(q *apiv2.Quota, p string) bool {
+	return q.Path != nil && path.Clean(*q.Path) == path.Clean(p)
+}
+
+func isPersonaQuotaType(quotaType apiv2.QuotaType) bool {
+	return quotaType == apiv2.QuotaTypeUser ||
+		quotaType == apiv2.QuotaTypeGroup
+}
+
+// personaMatches returns a flag indicating whether or not the actual
+// persona returned by the cluster is the target persona. Personas with IDs
+// are compared by ID, otherwise by type and name.
+func personaMatches(target, actual *apiv2.Persona) bool {
+	if actual == nil {
+		return false
+	}
+	if target.ID != nil {
+		return actual.ID != nil &&
+			actual.ID.Type == target.ID.Type &&
+			actual.ID.ID == target.ID.ID
+	}
+	if target.Type != nil &&
+		(actual.Type == nil || *actual.Type != *target.Type) {
+		return false
+	}
+	return target.Name != nil && actual.Name != nil &&
+		strings.EqualFold(*target.Name, *actual.Name)
+}
+
+// ApplyQuota creates or updates the directory quota of a volume so that it
+// matches the provided QuotaSpec. Nothing is sent to the cluster if the
+// quota already matches. Since a quota's IncludeSnapshots setting cannot be
+// modified, an error is returned if it differs from that of an existing
+// quota.
+func (c *Client) ApplyQuota(
+	ctx context.Context, name string, spec *QuotaSpec) error {
+
+	return c.ApplyPersonaQuota(
+		ctx, name, apiv2.QuotaTypeDirectory, nil, spec)
+}
+
+// ApplyPersonaQuota creates or updates a quota of the provided type on a
+// volume so that it matches the provided QuotaSpec. The persona is required
+// for user and group quotas and ignored for the other types. A user or group
+// quota created from a default quota is unlinked from it when updated.
+func (c *Client) ApplyPersonaQuota(
+	ctx context.Context,
+	name string,
+	quotaType apiv2.QuotaType,
+	persona *apiv2.Persona,
+	spec *QuotaSpec) error {
+
+	if err := spec.Validate(); err != nil {
+		return err
+	}
+	if isPersonaQuotaType(quotaType) && persona == nil {
+		return errors.New("quota type requires a persona: " + quotaType.String())
+	}
+
+	var (
+		volPath  = c.API.VolumePath(name)
+		enforced = !spec.AccountingOnly
+	)
+	quota, err := c.findQuota(ctx, volPath, quotaType, persona)
+	if err != nil {
+		return err
+	}
+
+	if quota == nil {
+		q := &apiv2.Quota{
+			Path:                      &volPath,
+			Type:                      &quotaType,
+			IncludeSnapshots:          &spec.IncludeSnapshots,
+			ThresholdsIncludeOverhead: &spec.ThresholdsIncludeOverhead,
+			Container:                 &spec.Container,
+			Enforced:                  &enforced,
+			Thresholds:                spec.thresholds(),
+		}
+		if isPersonaQuotaType(quotaType) {
+			q.Persona = persona
+		}
+		_, err := apiv2.QuotaCreate(ctx, c.API, q)
+		return err
+	}
+
+	current := quotaSpecFromQuota(quota)
+	if *current == *spec {
+		return nil
+	}
+	if current.IncludeSnapshots != spec.IncludeSnapshots {
+		return errors.New(
+			"quota include_snapshots cannot be modified: " + volPath)
+	}
+
+	q := &apiv2.Quota{
+		ID:                        quota.ID,
+		ThresholdsIncludeOverhead: &spec.ThresholdsIncludeOverhead,
+		Container:                 &spec.Container,
+		Enforced:                  &enforced,
+		Thresholds:                spec.thresholds(),
+	}
+	if quota.Linked != nil && *quota.Linked {
+		unlinked := false
+		q.Linked = &unlinked
+	}
+	return apiv2.QuotaUpdate(ctx, c.API, q)
+}
+
+// SetUserQuota creates or updates the quota of a user on a volume.
+func (c *Client) SetUserQuota(
+	ctx context.Context, name, user string, spec *QuotaSpec) error {
+
+	return c.ApplyPersonaQuota(
+		ctx, name, apiv2.QuotaTypeUser, userPersona(user), spec)
+}
+
+// SetGroupQuota creates or updates the quota of a group on a volume.
+func (c *Client) SetGroupQuota(
+	ctx context.Context, name, group string, spec *QuotaSpec) error {
+
+	return c.ApplyPersonaQuota(
+		ctx, name, apiv2.QuotaTypeGroup, groupPersona(group), spec)
+}
+
+// SetDefaultUserQuota creates or updates the default user quota of a volume,
+// which is applied to every user that writes to the volume.
+func (c *Client) SetDefaultUserQuota(
+	ctx context.Context, name string, spec *QuotaSpec) error {
+
+	return c.ApplyPersonaQuota(
+		ctx, name, apiv2.QuotaTypeDefaultUser, nil, spec)
+}
+
+// SetDefaultGroupQuota creates or updates the default group quota of a
+// volume, which is applied to every group that writes to the volume.
+func (c *Client) SetDefaultGroupQuota(
+	ctx context.Context, name string, spec *QuotaSpec) error {
+
+	return c.ApplyPersonaQuota(
+		ctx, name, apiv2.QuotaTypeDefaultGroup, nil, spec)
+}
+
+// GetQuotaSpec returns the QuotaSpec that describes the directory quota of a
+// volume, or nil if the volume has no quota.
+func (c *Client) GetQuotaSpec(
+	ctx context.Context, name string) (*QuotaSpec, error) {
+
+	return c.GetPersonaQuotaSpec(ctx, name, apiv2.QuotaTypeDirectory, nil)
+}
+
+// GetUserQuotaSpec returns the QuotaSpec that describes the quota of a user
+// on a volume, or nil if the user has no quota.
+func (c *Client) GetUserQuotaSpec(
+	ctx context.Context, name, user string) (*QuotaSpec, error) {
+
+	return c.GetPersonaQuotaSpec(
+		ctx, name, apiv2.QuotaTypeUser, userPersona(user))
+}
+
+// GetGroupQuotaSpec returns the QuotaSpec that describes the quota of a
+// group on a volume, or nil if the group has no quota.
+func (c *Client) GetGroupQuotaSpec(
+	ctx context.Context, name, group string) (*QuotaSpec, error) {
+
+	return c.GetPersonaQuotaSpec(
+		ctx, name, apiv2.QuotaTypeGroup, groupPersona(group))
+}
+
+// GetPersonaQuotaSpec returns the QuotaSpec that describes a quota of the
+// provided type on a volume, or nil if there is no such quota.
+func (c *Client) GetPersonaQuotaSpec(
+	ctx context.Context,
+	name string,
+	quotaType apiv2.QuotaType,
+	persona *apiv2.Persona) (*QuotaSpec, error) {
+
+	quota, err := c.findQuota(
+		ctx, c.API.VolumePath(name), quotaType, persona)
+	if err != nil || quota == nil {
+		return nil, err
+	}
+	return quotaSpecFromQuota(quota), nil
+}
+
+// GetQuotaUsage returns the usage accounted by the directory quota of a
+// volume, or nil if the volume has no quota.
+func (c *Client) GetQuotaUsage(
+	ctx context.Context, name string) (*apiv2.QuotaUsage, error) {
+
+	quota, err := c.findQuota(
+		ctx, c.API.VolumePath(name), apiv2.QuotaTypeDirectory, nil)
+	if err != nil || quota == nil {
+		return nil, err
+	}
+	return quota.Usage, nil
+}
+
+// ClearPersonaQuota removes a quota of the provided type from a volume.
+func (c *Client) ClearPersonaQuota(
+	ctx context.Context,
+	name string,
+	quotaType apiv2.QuotaType,
+	persona *apiv2.Persona) error {
+
+	quota, err := c.findQuota(
+		ctx, c.API.VolumePath(name), quotaType, persona)
+	if err != nil || quota == nil {
+		return err
+	}
+	return apiv2.QuotaDelete(ctx, c.API, quota.ID)
+}
+
+// GetVolumeQuotas returns the quotas of all types on a volume that match the
+// provided filter. The filter's path is set to that of the volume. If the
+// filter is nil then all of the volume's quotas are returned.
+func (c *Client) GetVolumeQuotas(
+	ctx context.Context,
+	name string,
+	filter *apiv2.QuotaFilter) ([]*apiv2.Quota, error) {
+
+	var f apiv2.QuotaFilter
+	if filter != nil {
+		f = *filter
+	}
+	volPath := c.API.VolumePath(name)
+	f.Path = &volPath
+
+	quotas, err := apiv2.QuotasListWithFilter(ctx, c.API, &f)
+	if err != nil {
+		return nil, err
+	}
+	if f.RecursePathChildren {
+		return quotas, nil
+	}
+
+	// only keep the quotas of the volume itself
+	var volQuotas []*apiv2.Quota
+	for _, q := range quotas {
+		if quotaPathIs(q, volPath) {
+			volQuotas = append(volQuotas, q)
+		}
+	}
+	return volQuotas, nil
+}
+
+func userPersona(name string) *apiv2.Persona {
+	return &apiv2.Persona{Type: &apiv2.PPersonaTypeUser, Name: &name}
+}
+
+func groupPersona(name string) *apiv2.Persona {
+	return &apiv2.Persona{Type: &apiv2.PPersonaTypeGroup, Name: &name}
+}
@@ -0,0 +1,234 @@
+package goisilon
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	apiv2 "github.com/thecodeteam/goisilon/api/v2"
+)
+
+// QuotaGrowthRule describes when and how much the hard threshold of a
+// volume's directory quota grows.
+type QuotaGrowthRule struct {
+
+	// Volume is the name of the volume.
+	Volume string
+
+	// UsagePercent is the percentage of the hard threshold that the quota's
+	// usage must reach for the quota to grow.
+	UsagePercent float64
+
+	// GrowPercent is the percentage by which the hard threshold grows.
+	GrowPercent float64
+
+	// Ceiling is the size in bytes past which the hard threshold never
+	// grows.
+	Ceiling int64
+
+	// Cooldown is the minimum time between two growths of the quota.
+	Cooldown time.Duration
+}
+
+// Validate returns an error if the rule cannot be evaluated.
+func (r *QuotaGrowthRule) Validate() error {
+	if r.Volume == "" {
+		return errors.New("quota growth rule has no volume")
+	}
+	if r.UsagePercent <= 0 {
+		return errors.New("quota growth usage percent must be positive")
+	}
+	if r.GrowPercent <= 0 {
+		return errors.New("quota growth percent must be positive")
+	}
+	if r.Ceiling <= 0 {
+		return errors.New("quota growth ceiling must be positive")
+	}
+	if r.Cooldown < 0 {
+		return errors.New("quota growth cooldown is negative")
+	}
+	return nil
+}
+
+// QuotaGrowthRecord is the audit record of a quota growth.
+type QuotaGrowthRecord struct {
+	Volume       string
+	QuotaID      string
+	Time         time.Time
+	Usage        int64
+	UsagePercent float64
+	OldHard      int64
+	NewHard      int64
+
+	// DryRun indicates whether or not the growth was only planned.
+	DryRun bool
+
+	// Err is the error that prevented the growth from being applied.
+	Err error
+}
+
+// String returns the record as a line of an audit log.
+func (r *QuotaGrowthRecord) String() string {
+	s := fmt.Sprintf(
+		"%s volume=%s quota=%s usage=%d (%.2f%%) hard=%d->%d",
+		r.Time.UTC().Format(time.RFC3339), r.Volume, r.QuotaID,
+		r.Usage, r.UsagePercent, r.OldHard, r.NewHard)
+	if r.DryRun {
+		s += " dry-run"
+	}
+	if r.Err != nil {
+		s += " error=" + r.Err.Error()
+	}
+	return s
+}
+
+// QuotaAutoscaler grows the hard thresholds of volumes' directory quotas
+// according to QuotaGrowthRules. The quotas are evaluated once per call to
+// Plan or Run, so it is up to the caller to schedule them.
+type QuotaAutoscaler struct {
+	client *Client
+	rules  []*QuotaGrowthRule
+	now    func() time.Time
+
+	lock       sync.Mutex
+	lastGrowth map[string]time.Time
+}
+
+// NewQuotaAutoscaler returns a new QuotaAutoscaler for the provided rules.
+func NewQuotaAutoscaler(
+	client *Client, rules ...*QuotaGrowthRule) (*QuotaAutoscaler, error) {
+
+	for _, r := range rules {
+		if err := r.Validate(); err != nil {
+			return nil, err
+		}
+	}
+	return &QuotaAutoscaler{
+		client:     client,
+		rules:      rules,
+		now:        time.Now,
+		lastGrowth: map[string]time.Time{},
+	}, nil
+}
+
+// Restore seeds the autoscaler's cooldowns from the records of previous
+// runs, for example after a restart. Dry-run and failed records are ignored.
+func (a *QuotaAutoscaler) Restore(records []*QuotaGrowthRecord) {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	for _, r := range records {
+		if r.DryRun || r.Err != nil {
+			continue
+		}
+		if r.Time.After(a.lastGrowth[r.Volume]) {
+			a.lastGrowth[r.Volume] = r.Time
+		}
+	}
+}
+
+// Plan returns the records of the growths that Run would apply without
+// applying them.
+func (a *QuotaAutoscaler) Plan(
+	ctx context.Context) ([]*QuotaGrowthRecord, error) {
+
+	return a.run(ctx, true)
+}
+
+// Run grows the quotas that meet their rules and returns a record for each
+// growth. A growth that fails is recorded with its error and does not stop
+// the other quotas from growing.
+func (a *QuotaAutoscaler) Run(
+	ctx context.Context) ([]*QuotaGrowthRecord, error) {
+
+	return a.run(ctx, false)
+}
+
+func (a *QuotaAutoscaler) run(
+	ctx context.Context, dryRun bool) ([]*QuotaGrowthRecord, error) {
+
+	var records []*QuotaGrowthRecord
+	for _, r := range a.rules {
+		quota, err := a.client.findQuota(
+			ctx, a.client.API.VolumePath(r.Volume),
+			apiv2.QuotaTypeDirectory, nil)
+		if err != nil {
+			return records, err
+		}
+		if quota == nil {
+			continue
+		}
+
+		now := a.now()
+		a.lock.Lock()
+		last := a.lastGrowth[r.Volume]
+		a.lock.Unlock()
+
+		record := r.evaluate(quota, now, last)
+		if record == nil {
+			continue
+		}
+		record.DryRun = dryRun
+		records = append(records, record)
+		if dryRun {
+			continue
+		}
+
+		spec := quotaSpecFromQuota(quota)
+		spec.Hard = record.NewHard
+		record.Err = apiv2.QuotaUpdate(ctx, a.client.API, &apiv2.Quota{
+			ID:         quota.ID,
+			Thresholds: spec.thresholds(),
+		})
+		if record.Err == nil {
+			a.lock.Lock()
+			a.lastGrowth[r.Volume] = now
+			a.lock.Unlock()
+		}
+	}
+	return records, nil
+}
+
+// evaluate returns the record of the growth of a quota, or nil if the quota
+// should not grow. The last time is when the quota last grew.
+func (r *QuotaGrowthRule) evaluate(
+	quota *apiv2.Quota, now, last time.Time) *QuotaGrowthRecord {
+
+	if quota.Thresholds == nil || int64Value(quota.Thresholds.Hard) == 0 ||
+		quota.Usage == nil {
+		return nil
+	}
+	if !last.IsZero() && now.Sub(last) < r.Cooldown {
+		return nil
+	}
+
+	hard := *quota.Thresholds.Hard
+	if hard >= r.Ceiling {
+		return nil
+	}
+
+	usage := int64Value(quota.Usage.Logical)
+	if boolValue(quota.ThresholdsIncludeOverhead) {
+		usage = int64Value(quota.Usage.Physical)
+	}
+	pct := float64(usage) * 100 / float64(hard)
+	if pct < r.UsagePercent {
+		return nil
+	}
+
+	newHard := hard + int64(float64(hard)*r.GrowPercent/100)
+	if newHard > r.Ceiling {
+		newHard = r.Ceiling
+	}
+
+	return &QuotaGrowthRecord{
+		Volume:       r.Volume,
+		QuotaID:      quota.ID,
+		Time:         now,
+		Usage:        usage,
+		UsagePercent: pct,
+		OldHard:      hard,
+		NewHard:      newHard,
+	}
+}
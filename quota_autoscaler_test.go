@@ -0,0 +1,122 @@
+package goisilon
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	apiv2 "github.com/thecodeteam/goisilon/api/v2"
+)
+
+func TestQuotaAutoscaler(t *testing.T) {
+	volumeName := "test_quota_autoscaler"
+
+	_, err := client.CreateVolume(defaultCtx, volumeName)
+	assertNoError(t, err)
+	defer client.DeleteVolume(defaultCtx, volumeName)
+	defer client.ClearQuota(defaultCtx, volumeName)
+	assertNoError(t, client.ApplyQuota(
+		defaultCtx, volumeName, &QuotaSpec{Hard: 1024 * 1024}))
+
+	a, err := NewQuotaAutoscaler(client, &QuotaGrowthRule{
+		Volume:       volumeName,
+		UsagePercent: 0.0001,
+		GrowPercent:  50,
+		Ceiling:      2 * 1024 * 1024,
+		Cooldown:     time.Hour,
+	})
+	assertNoError(t, err)
+
+	usage, err := client.GetQuotaUsage(defaultCtx, volumeName)
+	assertNoError(t, err)
+	if usage == nil || usage.Logical == nil || *usage.Logical == 0 {
+		t.Skip("quota has not accounted any usage yet")
+	}
+
+	records, err := a.Plan(defaultCtx)
+	assertNoError(t, err)
+	assertLen(t, records, 1)
+	assert.True(t, records[0].DryRun)
+
+	records, err = a.Run(defaultCtx)
+	assertNoError(t, err)
+	assertLen(t, records, 1)
+	assertNoError(t, records[0].Err)
+
+	spec, err := client.GetQuotaSpec(defaultCtx, volumeName)
+	assertNoError(t, err)
+	assert.Equal(t, int64(1536*1024), spec.Hard)
+
+	// the rule is cooling down
+	records, err = a.Run(defaultCtx)
+	assertNoError(t, err)
+	assertLen(t, records, 0)
+}
+
+func TestQuotaGrowthRuleEvaluate(t *testing.T) {
+	var (
+		hard  = int64(1000)
+		used  = int64(850)
+		phys  = int64(2000)
+		quota = &apiv2.Quota{
+			ID:         "q1",
+			Thresholds: &apiv2.QuotaThresholds{Hard: &hard},
+			Usage:      &apiv2.QuotaUsage{Logical: &used, Physical: &phys},
+		}
+		rule = &QuotaGrowthRule{
+			Volume:       "vol1",
+			UsagePercent: 80,
+			GrowPercent:  50,
+			Ceiling:      1200,
+			Cooldown:     time.Hour,
+		}
+		now = time.Unix(1500000000, 0)
+	)
+	assert.NoError(t, rule.Validate())
+
+	r := rule.evaluate(quota, now, time.Time{})
+	if !assert.NotNil(t, r) {
+		t.FailNow()
+	}
+	assert.Equal(t, int64(1000), r.OldHard)
+	assert.Equal(t, int64(1200), r.NewHard)
+	assert.Equal(t, 85.0, r.UsagePercent)
+
+	// cooling down
+	assert.Nil(t, rule.evaluate(quota, now, now.Add(-time.Minute)))
+	assert.NotNil(t, rule.evaluate(quota, now, now.Add(-2*time.Hour)))
+
+	// below the usage percent
+	rule.UsagePercent = 90
+	assert.Nil(t, rule.evaluate(quota, now, time.Time{}))
+
+	// physical usage counts when thresholds include overhead
+	yes := true
+	quota.ThresholdsIncludeOverhead = &yes
+	r = rule.evaluate(quota, now, time.Time{})
+	if assert.NotNil(t, r) {
+		assert.Equal(t, int64(2000), r.Usage)
+	}
+
+	// at the ceiling
+	rule.Ceiling = 1000
+	assert.Nil(t, rule.evaluate(quota, now, time.Time{}))
+}
+
+func TestQuotaAutoscalerRestore(t *testing.T) {
+	a, err := NewQuotaAutoscaler(nil)
+	assert.NoError(t, err)
+	now := time.Unix(1500000000, 0)
+	a.Restore([]*QuotaGrowthRecord{
+		{Volume: "vol1", Time: now.Add(-time.Hour)},
+		{Volume: "vol1", Time: now},
+		{Volume: "vol1", Time: now.Add(time.Hour), DryRun: true},
+		{Volume: "vol2", Time: now},
+	})
+	assert.Equal(t, now, a.lastGrowth["vol1"])
+	assert.Equal(t, now, a.lastGrowth["vol2"])
+
+	_, err = NewQuotaAutoscaler(nil, &QuotaGrowthRule{Volume: "vol1"})
+	assert.Error(t, err)
+}
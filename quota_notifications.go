@@ -0,0 +1,337 @@
+package goisilon
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	apiv2 "github.com/thecodeteam/goisilon/api/v2"
+)
+
+// QuotaNotificationRule notifies when a threshold of a quota meets a
+// condition.
+type QuotaNotificationRule struct {
+
+	// ID is the rule's ID. It is assigned by the cluster.
+	ID string
+
+	// Condition is the condition of the threshold that triggers the rule.
+	Condition apiv2.QuotaNotificationCondition
+
+	// Threshold is the threshold the condition applies to.
+	Threshold apiv2.QuotaThreshold
+
+	// Schedule, if set, repeats the notification while the condition is
+	// met.
+	Schedule QuotaNotificationSchedule
+
+	// Holdoff is the minimum time between two notifications. It is
+	// truncated to seconds.
+	Holdoff time.Duration
+
+	// Actions are what the rule does when it is triggered.
+	Actions []QuotaNotificationAction
+
+	// EmailTemplate, if set, is the path of the template used for emails. If
+	// empty, the cluster's default template is used.
+	EmailTemplate string
+}
+
+// QuotaNotificationSchedule is a OneFS schedule, such as
+// "every 1 days at 09:00".
+type QuotaNotificationSchedule string
+
+// DailyQuotaNotificationSchedule returns a schedule that repeats every day
+// at the provided time.
+func DailyQuotaNotificationSchedule(hour, min int) QuotaNotificationSchedule {
+	return QuotaNotificationSchedule(
+		fmt.Sprintf("every 1 days at %02d:%02d", hour, min))
+}
+
+// WeeklyQuotaNotificationSchedule returns a schedule that repeats every
+// week on the provided day at the provided time.
+func WeeklyQuotaNotificationSchedule(
+	day time.Weekday, hour, min int) QuotaNotificationSchedule {
+
+	return QuotaNotificationSchedule(fmt.Sprintf(
+		"every %s at %02d:%02d", strings.ToLower(day.String()), hour, min))
+}
+
+// QuotaNotificationActionKind is the kind of a QuotaNotificationAction.
+type QuotaNotificationActionKind uint8
+
+const (
+	// QuotaNotificationAlert raises a cluster event.
+	QuotaNotificationAlert QuotaNotificationActionKind = iota + 1
+
+	// QuotaNotificationEmailOwner emails the owner of the quota's data.
+	QuotaNotificationEmailOwner
+
+	// QuotaNotificationEmail emails an address.
+	QuotaNotificationEmail
+)
+
+// QuotaNotificationAction is an action taken by a QuotaNotificationRule.
+type QuotaNotificationAction struct {
+	Kind QuotaNotificationActionKind
+
+	// Address is the email address of a QuotaNotificationEmail action.
+	Address string
+}
+
+// Validate returns an error if the rule cannot be applied to a quota.
+func (r *QuotaNotificationRule) Validate() error {
+	if r.Condition == apiv2.QuotaNotificationConditionUnknown {
+		return errors.New("quota notification rule has no condition")
+	}
+	if r.Threshold == apiv2.QuotaThresholdUnknown {
+		return errors.New("quota notification rule has no threshold")
+	}
+	if (r.Condition == apiv2.QuotaNotificationConditionViolatedGrace ||
+		r.Condition == apiv2.QuotaNotificationConditionExpired) &&
+		r.Threshold != apiv2.QuotaThresholdSoft {
+		return fmt.Errorf(
+			"quota notification condition %s only applies to the soft threshold",
+			r.Condition)
+	}
+	if r.Holdoff < 0 {
+		return errors.New("quota notification holdoff is negative")
+	}
+	if len(r.Actions) == 0 {
+		return errors.New("quota notification rule has no actions")
+	}
+	emails := 0
+	for _, a := range r.Actions {
+		switch a.Kind {
+		case QuotaNotificationAlert, QuotaNotificationEmailOwner:
+		case QuotaNotificationEmail:
+			if a.Address == "" {
+				return errors.New("quota notification email has no address")
+			}
+			emails++
+		default:
+			return fmt.Errorf("invalid quota notification action: %d", a.Kind)
+		}
+	}
+	if emails > 1 {
+		return errors.New("quota notification rule has more than one email")
+	}
+	return nil
+}
+
+// notification returns the rule as an apiv2.QuotaNotification. Every action
+// field and the email template are set so that actions and a template
+// removed from the rule are cleared when it is updated.
+func (r *QuotaNotificationRule) notification() *apiv2.QuotaNotification {
+	var (
+		condition = r.Condition
+		threshold = r.Threshold
+		schedule  = string(r.Schedule)
+		holdoff   = int(r.Holdoff / time.Second)
+		alert     bool
+		owner     bool
+		address   string
+		template  = r.EmailTemplate
+	)
+	for _, a := range r.Actions {
+		switch a.Kind {
+		case QuotaNotificationAlert:
+			alert = true
+		case QuotaNotificationEmailOwner:
+			owner = true
+		case QuotaNotificationEmail:
+			address = a.Address
+		}
+	}
+	return &apiv2.QuotaNotification{
+		ID:                 r.ID,
+		Condition:          &condition,
+		Threshold:          &threshold,
+		Schedule:           &schedule,
+		Holdoff:            &holdoff,
+		ActionAlert:        &alert,
+		ActionEmailOwner:   &owner,
+		ActionEmailAddress: &address,
+		EmailTemplate:      &template,
+	}
+}
+
+// newQuotaNotificationRule returns the rule described by an
+// apiv2.QuotaNotification.
+func newQuotaNotificationRule(
+	n *apiv2.QuotaNotification) *QuotaNotificationRule {
+
+	r := &QuotaNotificationRule{ID: n.ID}
+	if n.Condition != nil {
+		r.Condition = *n.Condition
+	}
+	if n.Threshold != nil {
+		r.Threshold = *n.Threshold
+	}
+	if n.Schedule != nil {
+		r.Schedule = QuotaNotificationSchedule(*n.Schedule)
+	}
+	if n.Holdoff != nil {
+		r.Holdoff = time.Duration(*n.Holdoff) * time.Second
+	}
+	if boolValue(n.ActionAlert) {
+		r.Actions = append(
+			r.Actions, QuotaNotificationAction{Kind: QuotaNotificationAlert})
+	}
+	if boolValue(n.ActionEmailOwner) {
+		r.Actions = append(
+			r.Actions, QuotaNotificationAction{Kind: QuotaNotificationEmailOwner})
+	}
+	if n.ActionEmailAddress != nil && *n.ActionEmailAddress != "" {
+		r.Actions = append(r.Actions, QuotaNotificationAction{
+			Kind:    QuotaNotificationEmail,
+			Address: *n.ActionEmailAddress,
+		})
+	}
+	if n.EmailTemplate != nil {
+		r.EmailTemplate = *n.EmailTemplate
+	}
+	return r
+}
+
+// volumeQuotaID returns the ID of the directory quota of a volume.
+func (c *Client) volumeQuotaID(ctx context.Context, name string) (string, error) {
+	path := c.API.VolumePath(name)
+	quota, err := c.findQuota(ctx, path, apiv2.QuotaTypeDirectory, nil)
+	if err != nil {
+		return "", err
+	}
+	if quota == nil {
+		return "", fmt.Errorf("Quota not found: %s", path)
+	}
+	return quota.ID, nil
+}
+
+// GetQuotaNotificationRules returns the notification rules of the directory
+// quota of a volume. A quota without rules of its own uses the default
+// rules.
+func (c *Client) GetQuotaNotificationRules(
+	ctx context.Context, name string) ([]*QuotaNotificationRule, error) {
+
+	id, err := c.volumeQuotaID(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	return c.getQuotaNotificationRules(ctx, id)
+}
+
+// AddQuotaNotificationRule adds a notification rule to the directory quota
+// of a volume and returns the rule's ID.
+func (c *Client) AddQuotaNotificationRule(
+	ctx context.Context,
+	name string,
+	rule *QuotaNotificationRule) (string, error) {
+
+	id, err := c.volumeQuotaID(ctx, name)
+	if err != nil {
+		return "", err
+	}
+	return c.addQuotaNotificationRule(ctx, id, rule)
+}
+
+// UpdateQuotaNotificationRule updates the notification rule of the
+// directory quota of a volume with the rule's ID. A rule's condition and
+// threshold cannot be modified.
+func (c *Client) UpdateQuotaNotificationRule(
+	ctx context.Context,
+	name string,
+	rule *QuotaNotificationRule) error {
+
+	id, err := c.volumeQuotaID(ctx, name)
+	if err != nil {
+		return err
+	}
+	return c.updateQuotaNotificationRule(ctx, id, rule)
+}
+
+// DeleteQuotaNotificationRule deletes a notification rule of the directory
+// quota of a volume.
+func (c *Client) DeleteQuotaNotificationRule(
+	ctx context.Context, name, ruleID string) error {
+
+	id, err := c.volumeQuotaID(ctx, name)
+	if err != nil {
+		return err
+	}
+	return apiv2.QuotaNotificationDelete(ctx, c.API, id, ruleID)
+}
+
+// GetDefaultQuotaNotificationRules returns the cluster's default quota
+// notification rules.
+func (c *Client) GetDefaultQuotaNotificationRules(
+	ctx context.Context) ([]*QuotaNotificationRule, error) {
+
+	return c.getQuotaNotificationRules(ctx, "")
+}
+
+// AddDefaultQuotaNotificationRule adds a rule to the cluster's default
+// quota notification rules and returns the rule's ID.
+func (c *Client) AddDefaultQuotaNotificationRule(
+	ctx context.Context, rule *QuotaNotificationRule) (string, error) {
+
+	return c.addQuotaNotificationRule(ctx, "", rule)
+}
+
+// UpdateDefaultQuotaNotificationRule updates the default quota notification
+// rule with the rule's ID.
+func (c *Client) UpdateDefaultQuotaNotificationRule(
+	ctx context.Context, rule *QuotaNotificationRule) error {
+
+	return c.updateQuotaNotificationRule(ctx, "", rule)
+}
+
+// DeleteDefaultQuotaNotificationRule deletes a default quota notification
+// rule.
+func (c *Client) DeleteDefaultQuotaNotificationRule(
+	ctx context.Context, ruleID string) error {
+
+	return apiv2.QuotaNotificationDelete(ctx, c.API, "", ruleID)
+}
+
+func (c *Client) getQuotaNotificationRules(
+	ctx context.Context, quotaID string) ([]*QuotaNotificationRule, error) {
+
+	notifications, err := apiv2.QuotaNotificationsList(ctx, c.API, quotaID)
+	if err != nil {
+		return nil, err
+	}
+	rules := make([]*QuotaNotificationRule, len(notifications))
+	for i, n := range notifications {
+		rules[i] = newQuotaNotificationRule(n)
+	}
+	return rules, nil
+}
+
+func (c *Client) addQuotaNotificationRule(
+	ctx context.Context,
+	quotaID string,
+	rule *QuotaNotificationRule) (string, error) {
+
+	if err := rule.Validate(); err != nil {
+		return "", err
+	}
+	return apiv2.QuotaNotificationCreate(
+		ctx, c.API, quotaID, rule.notification())
+}
+
+func (c *Client) updateQuotaNotificationRule(
+	ctx context.Context,
+	quotaID string,
+	rule *QuotaNotificationRule) error {
+
+	if rule.ID == "" {
+		return errors.New("quota notification rule has no ID")
+	}
+	if err := rule.Validate(); err != nil {
+		return err
+	}
+	return apiv2.QuotaNotificationUpdate(
+		ctx, c.API, quotaID, rule.notification())
+}
@@ -0,0 +1,98 @@
+package goisilon
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	apiv2 "github.com/thecodeteam/goisilon/api/v2"
+)
+
+func TestQuotaNotificationRules(t *testing.T) {
+	volumeName := "test_quota_notification_rules"
+
+	_, err := client.CreateVolume(defaultCtx, volumeName)
+	assertNoError(t, err)
+	defer client.DeleteVolume(defaultCtx, volumeName)
+	defer client.ClearQuota(defaultCtx, volumeName)
+	assertNoError(t, client.ApplyQuota(
+		defaultCtx, volumeName,
+		&QuotaSpec{Hard: 10 * 1024 * 1024, Advisory: 5 * 1024 * 1024}))
+
+	rule := &QuotaNotificationRule{
+		Condition: apiv2.QuotaNotificationConditionExceeded,
+		Threshold: apiv2.QuotaThresholdAdvisory,
+		Holdoff:   time.Hour,
+		Actions: []QuotaNotificationAction{
+			{Kind: QuotaNotificationAlert},
+		},
+	}
+	rule.ID, err = client.AddQuotaNotificationRule(defaultCtx, volumeName, rule)
+	assertNoError(t, err)
+
+	rule.Actions = append(rule.Actions, QuotaNotificationAction{
+		Kind: QuotaNotificationEmailOwner})
+	assertNoError(t, client.UpdateQuotaNotificationRule(
+		defaultCtx, volumeName, rule))
+
+	rules, err := client.GetQuotaNotificationRules(defaultCtx, volumeName)
+	assertNoError(t, err)
+	assertLen(t, rules, 1)
+	assert.Equal(t, rule.Actions, rules[0].Actions)
+
+	assertNoError(t, client.DeleteQuotaNotificationRule(
+		defaultCtx, volumeName, rule.ID))
+}
+
+func TestQuotaNotificationRuleRoundTrip(t *testing.T) {
+	rule := &QuotaNotificationRule{
+		ID:        "0123",
+		Condition: apiv2.QuotaNotificationConditionViolatedGrace,
+		Threshold: apiv2.QuotaThresholdSoft,
+		Schedule:  WeeklyQuotaNotificationSchedule(time.Monday, 9, 30),
+		Holdoff:   5 * time.Minute,
+		Actions: []QuotaNotificationAction{
+			{Kind: QuotaNotificationAlert},
+			{Kind: QuotaNotificationEmail, Address: "ops@example.com"},
+		},
+	}
+	assert.NoError(t, rule.Validate())
+	assert.Equal(t,
+		QuotaNotificationSchedule("every monday at 09:30"), rule.Schedule)
+
+	n := rule.notification()
+	assert.False(t, *n.ActionEmailOwner)
+	if assert.NotNil(t, n.EmailTemplate) {
+		assert.Equal(t, "", *n.EmailTemplate)
+	}
+	assert.Equal(t, 300, *n.Holdoff)
+	assert.Equal(t, rule, newQuotaNotificationRule(n))
+}
+
+func TestQuotaNotificationRuleValidate(t *testing.T) {
+	alert := []QuotaNotificationAction{{Kind: QuotaNotificationAlert}}
+	assert.NoError(t, (&QuotaNotificationRule{
+		Condition: apiv2.QuotaNotificationConditionDenied,
+		Threshold: apiv2.QuotaThresholdHard,
+		Actions:   alert,
+	}).Validate())
+	assert.Error(t, (&QuotaNotificationRule{
+		Threshold: apiv2.QuotaThresholdHard,
+		Actions:   alert,
+	}).Validate())
+	assert.Error(t, (&QuotaNotificationRule{
+		Condition: apiv2.QuotaNotificationConditionExpired,
+		Threshold: apiv2.QuotaThresholdHard,
+		Actions:   alert,
+	}).Validate())
+	assert.Error(t, (&QuotaNotificationRule{
+		Condition: apiv2.QuotaNotificationConditionDenied,
+		Threshold: apiv2.QuotaThresholdHard,
+	}).Validate())
+	assert.Error(t, (&QuotaNotificationRule{
+		Condition: apiv2.QuotaNotificationConditionDenied,
+		Threshold: apiv2.QuotaThresholdHard,
+		Actions:   []QuotaNotificationAction{{Kind: QuotaNotificationEmail}},
+	}).Validate())
+}
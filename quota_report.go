@@ -0,0 +1,245 @@
+package goisilon
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"io"
+	"path"
+	"sort"
+	"strconv"
+	"time"
+
+	api "github.com/thecodeteam/goisilon/api/v1"
+)
+
+// QuotaReportOptions are the options used to build a QuotaReport.
+type QuotaReportOptions struct {
+
+	// ReportID, if set, is the ID of a quota report generated by the cluster,
+	// for example by a schedule, from which the QuotaReport is built. If
+	// empty then the QuotaReport is built from the cluster's live quotas.
+	ReportID string
+
+	// Time is the time recorded in the QuotaReport. It defaults to the time
+	// the cluster generated the report, or to the current time for live
+	// quotas.
+	Time time.Time
+}
+
+// QuotaReport is the usage of every volume with a directory quota, in any
+// of the client's volume roots.
+type QuotaReport struct {
+	Time    time.Time           `json:"time"`
+	Volumes []*VolumeQuotaUsage `json:"volumes"`
+}
+
+// VolumeQuotaUsage is the usage and thresholds of a volume's directory
+// quota. Thresholds that are not set are zero.
+type VolumeQuotaUsage struct {
+	Volume           string  `json:"volume"`
+	Path             string  `json:"path"`
+	Logical          int64   `json:"logical"`
+	Physical         int64   `json:"physical"`
+	Inodes           int64   `json:"inodes"`
+	Hard             int64   `json:"hard"`
+	Soft             int64   `json:"soft"`
+	Advisory         int64   `json:"advisory"`
+	PercentUsed      float64 `json:"percent_used"`
+	HardExceeded     bool    `json:"hard_exceeded"`
+	SoftExceeded     bool    `json:"soft_exceeded"`
+	AdvisoryExceeded bool    `json:"advisory_exceeded"`
+}
+
+// GetQuotaReports returns the quota reports available on the cluster.
+func (c *Client) GetQuotaReports(
+	ctx context.Context) ([]*api.IsiQuotaReport, error) {
+
+	return api.GetIsiQuotaReports(ctx, c.API)
+}
+
+// QuotaReport returns the usage of every volume with a directory quota in
+// any of the client's volume roots, ordered by volume name and path.
+func (c *Client) QuotaReport(
+	ctx context.Context, opts *QuotaReportOptions) (*QuotaReport, error) {
+
+	if opts == nil {
+		opts = &QuotaReportOptions{}
+	}
+
+	var (
+		quotas       []*api.IsiQuota
+		reportTime   = opts.Time
+		roots        = c.volumeRoots()
+		volumesPaths = make([]string, len(roots))
+	)
+	for i, r := range roots {
+		volumesPaths[i] = r.path
+	}
+
+	if opts.ReportID == "" {
+		for _, p := range volumesPaths {
+			rootQuotas, err := api.GetIsiQuotasByPath(ctx, c.API, p, true)
+			if isNotFound(err) {
+				continue
+			}
+			if err != nil {
+				return nil, err
+			}
+			quotas = append(quotas, rootQuotas...)
+		}
+		if reportTime.IsZero() {
+			reportTime = time.Now()
+		}
+	} else {
+		reportQuotas, err := api.GetIsiQuotaReportQuotas(
+			ctx, c.API, opts.ReportID)
+		if err != nil {
+			return nil, err
+		}
+		for i := range reportQuotas {
+			quotas = append(quotas, &reportQuotas[i])
+		}
+		if reportTime.IsZero() {
+			reportTime, err = c.quotaReportTime(ctx, opts.ReportID)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return newQuotaReport(volumesPaths, reportTime, quotas), nil
+}
+
+// quotaReportTime returns the time at which the cluster generated the quota
+// report with the provided ID. An error is returned if the cluster has no
+// such report.
+func (c *Client) quotaReportTime(
+	ctx context.Context, id string) (time.Time, error) {
+
+	reports, err := api.GetIsiQuotaReports(ctx, c.API)
+	if err != nil {
+		return time.Time{}, err
+	}
+	for _, r := range reports {
+		if r.Id == id {
+			return time.Unix(r.Time, 0), nil
+		}
+	}
+	return time.Time{}, errors.New("quota report not found: " + id)
+}
+
+// newQuotaReport builds a QuotaReport from the directory quotas of the
+// volumes in the provided volumes paths. A quota listed more than once, for
+// example because one volumes path is inside of another, is only reported
+// once.
+func newQuotaReport(
+	volumesPaths []string,
+	reportTime time.Time,
+	quotas []*api.IsiQuota) *QuotaReport {
+
+	var (
+		report = &QuotaReport{Time: reportTime}
+		roots  = map[string]bool{}
+		seen   = map[string]bool{}
+	)
+	for _, p := range volumesPaths {
+		roots[path.Clean(p)] = true
+	}
+	for _, q := range quotas {
+		p := path.Clean(q.Path)
+		if q.Type != "directory" || !roots[path.Dir(p)] || seen[p] {
+			continue
+		}
+		seen[p] = true
+		report.Volumes = append(report.Volumes, newVolumeQuotaUsage(q))
+	}
+	sort.Slice(report.Volumes, func(i, j int) bool {
+		a, b := report.Volumes[i], report.Volumes[j]
+		if a.Volume != b.Volume {
+			return a.Volume < b.Volume
+		}
+		return a.Path < b.Path
+	})
+	return report
+}
+
+func newVolumeQuotaUsage(q *api.IsiQuota) *VolumeQuotaUsage {
+	u := &VolumeQuotaUsage{
+		Volume:           path.Base(q.Path),
+		Path:             q.Path,
+		Logical:          q.Usage.Logical,
+		Physical:         q.Usage.Physical,
+		Inodes:           q.Usage.Inodes,
+		Hard:             q.Thresholds.Hard,
+		Soft:             q.Thresholds.Soft,
+		Advisory:         q.Thresholds.Advisory,
+		HardExceeded:     q.Thresholds.HardExceeded,
+		SoftExceeded:     q.Thresholds.SoftExceeded,
+		AdvisoryExceeded: q.Thresholds.AdvisoryExceeded,
+	}
+
+	// the percentage is of the lowest enforcing threshold, measured the
+	// same way the cluster measures the quota's usage against it
+	limit := u.Hard
+	if u.Soft > 0 && (limit == 0 || u.Soft < limit) {
+		limit = u.Soft
+	}
+	if limit > 0 {
+		used := u.Logical
+		if q.ThresholdsIncludeOverhead {
+			used = u.Physical
+		}
+		u.PercentUsed = float64(used) * 100 / float64(limit)
+	}
+	return u
+}
+
+var quotaReportCSVHeader = []string{
+	"volume",
+	"path",
+	"logical",
+	"physical",
+	"inodes",
+	"hard",
+	"soft",
+	"advisory",
+	"percent_used",
+	"hard_exceeded",
+	"soft_exceeded",
+	"advisory_exceeded",
+}
+
+// WriteCSV writes the report as CSV with a header row.
+func (r *QuotaReport) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(quotaReportCSVHeader); err != nil {
+		return err
+	}
+	for _, u := range r.Volumes {
+		if err := cw.Write([]string{
+			u.Volume,
+			u.Path,
+			strconv.FormatInt(u.Logical, 10),
+			strconv.FormatInt(u.Physical, 10),
+			strconv.FormatInt(u.Inodes, 10),
+			strconv.FormatInt(u.Hard, 10),
+			strconv.FormatInt(u.Soft, 10),
+			strconv.FormatInt(u.Advisory, 10),
+			strconv.FormatFloat(u.PercentUsed, 'f', 2, 64),
+			strconv.FormatBool(u.HardExceeded),
+			strconv.FormatBool(u.SoftExceeded),
+			strconv.FormatBool(u.AdvisoryExceeded),
+		}); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// WriteJSON writes the report as JSON.
+func (r *QuotaReport) WriteJSON(w io.Writer) error {
+	return json.NewEncoder(w).Encode(r)
+}
@@ -0,0 +1,134 @@
+package goisilon
+
+import (
+	"bytes"
+	"net/http"
+	"path"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	api "github.com/thecodeteam/goisilon/api/v1"
+)
+
+func TestQuotaReport(t *testing.T) {
+	report, err := client.QuotaReport(defaultCtx, nil)
+	assertNoError(t, err)
+	assertNotNil(t, report)
+	roots := map[string]bool{}
+	for _, r := range client.volumeRoots() {
+		roots[r.path] = true
+	}
+	for _, u := range report.Volumes {
+		assert.Equal(t, u.Volume, path.Base(u.Path))
+		assert.True(t, roots[path.Dir(u.Path)], u.Path)
+	}
+}
+
+const quotaReportXML = `<?xml version="1.0" encoding="UTF-8"?>
+<quota-report>
+  <domains>
+    <domain type="directory" path="/ifs/volumes/vol2" enforced="true">
+      <usage inodes="3" logical="500" physical="1500"/>
+      <hard-threshold exceeded="false">1000</hard-threshold>
+    </domain>
+    <domain type="directory" path="/ifs/volumes/vol1"
+        thresholds-include-overhead="true">
+      <usage inodes="10" logical="400" physical="1200"/>
+      <soft-threshold exceeded="true">1000</soft-threshold>
+      <hard-threshold exceeded="false">2000</hard-threshold>
+    </domain>
+    <domain type="user" path="/ifs/volumes/vol1">
+      <usage inodes="1" logical="100" physical="300"/>
+    </domain>
+    <domain type="directory" path="/ifs/volumes/vol1/nested">
+      <usage inodes="1" logical="100" physical="300"/>
+    </domain>
+  </domains>
+</quota-report>`
+
+func TestNewQuotaReport(t *testing.T) {
+	quotas, err := api.ParseIsiQuotaReport(strings.NewReader(quotaReportXML))
+	assert.NoError(t, err)
+	assert.Len(t, quotas, 4)
+
+	var pquotas []*api.IsiQuota
+	for i := range quotas {
+		pquotas = append(pquotas, &quotas[i])
+	}
+	reportTime := time.Unix(1500000000, 0).UTC()
+	report := newQuotaReport([]string{"/ifs/volumes"}, reportTime, pquotas)
+	if !assert.Len(t, report.Volumes, 2) {
+		t.FailNow()
+	}
+
+	vol1, vol2 := report.Volumes[0], report.Volumes[1]
+	assert.Equal(t, "vol1", vol1.Volume)
+	assert.Equal(t, int64(1000), vol1.Soft)
+	assert.True(t, vol1.SoftExceeded)
+	assert.Equal(t, 120.0, vol1.PercentUsed)
+	assert.Equal(t, "vol2", vol2.Volume)
+	assert.Equal(t, int64(0), vol2.Soft)
+	assert.Equal(t, 50.0, vol2.PercentUsed)
+
+	buf := &bytes.Buffer{}
+	assert.NoError(t, report.WriteCSV(buf))
+	assert.Equal(t,
+		"volume,path,logical,physical,inodes,hard,soft,advisory,"+
+			"percent_used,hard_exceeded,soft_exceeded,advisory_exceeded\n"+
+			"vol1,/ifs/volumes/vol1,400,1200,10,2000,1000,0,"+
+			"120.00,false,true,false\n"+
+			"vol2,/ifs/volumes/vol2,500,1500,3,1000,0,0,"+
+			"50.00,false,false,false\n",
+		buf.String())
+
+	buf.Reset()
+	assert.NoError(t, report.WriteJSON(buf))
+	assert.Contains(t, buf.String(), `"time":"2017-07-14T02:40:00Z"`)
+	assert.Contains(t, buf.String(), `"volume":"vol1"`)
+	assert.Contains(t, buf.String(), `"percent_used":120`)
+}
+
+func TestNewQuotaReportRoots(t *testing.T) {
+	quotas, err := api.ParseIsiQuotaReport(strings.NewReader(quotaReportXML))
+	assertNoError(t, err)
+
+	archived := quotas[0]
+	archived.Path = "/ifs/archive/vol2"
+	pquotas := []*api.IsiQuota{&quotas[0], &archived, &quotas[1], &quotas[0]}
+
+	report := newQuotaReport(
+		[]string{"/ifs/volumes", "/ifs/archive/"}, time.Time{}, pquotas)
+	var paths []string
+	for _, u := range report.Volumes {
+		paths = append(paths, u.Path)
+	}
+	assert.Equal(t, []string{
+		"/ifs/volumes/vol1", "/ifs/archive/vol2", "/ifs/volumes/vol2",
+	}, paths)
+}
+
+func TestQuotaReportUnknownReport(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/platform/1/quota/reports/r1":
+			w.Write([]byte(quotaReportXML))
+		case "/platform/1/quota/reports":
+			w.Write([]byte(`{"reports":[{"id":"r2","time":1500000000}]}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"errors":[{"message":"not found"}]}`))
+		}
+	}, nil)
+
+	_, err := c.QuotaReport(defaultCtx, &QuotaReportOptions{ReportID: "r1"})
+	assert.Error(t, err)
+
+	// a report time that is provided is not looked up
+	report, err := c.QuotaReport(defaultCtx, &QuotaReportOptions{
+		ReportID: "r1", Time: time.Unix(1, 0)})
+	assertNoError(t, err)
+	assertLen(t, report.Volumes, 2)
+}
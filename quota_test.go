@@ -1,8 +1,15 @@
 package goisilon
 
 import (
+	"encoding/json"
 	"fmt"
+	"net/http"
 	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	apiv2 "github.com/thecodeteam/goisilon/api/v2"
 )
 
 // Test both GetQuota() and SetQuota()
@@ -148,3 +155,136 @@ func TestQuotaClear(t *testing.T) {
 	}
 
 }
+
+func TestApplyQuota(t *testing.T) {
+	volumeName := "test_apply_quota"
+
+	_, err := client.CreateVolume(defaultCtx, volumeName)
+	assertNoError(t, err)
+	defer client.DeleteVolume(defaultCtx, volumeName)
+	defer client.ClearQuota(defaultCtx, volumeName)
+
+	spec := &QuotaSpec{
+		Hard:      10 * 1024 * 1024,
+		Soft:      8 * 1024 * 1024,
+		SoftGrace: time.Hour,
+		Advisory:  5 * 1024 * 1024,
+		Container: true,
+	}
+	assertNoError(t, client.ApplyQuota(defaultCtx, volumeName, spec))
+
+	// applying the same spec again is a no-op
+	assertNoError(t, client.ApplyQuota(defaultCtx, volumeName, spec))
+
+	actual, err := client.GetQuotaSpec(defaultCtx, volumeName)
+	assertNoError(t, err)
+	assertNotNil(t, actual)
+	assert.Equal(t, *spec, *actual)
+
+	spec.Soft, spec.SoftGrace = 0, 0
+	assertNoError(t, client.ApplyQuota(defaultCtx, volumeName, spec))
+	actual, err = client.GetQuotaSpec(defaultCtx, volumeName)
+	assertNoError(t, err)
+	assert.Equal(t, *spec, *actual)
+
+	spec.IncludeSnapshots = true
+	assert.Error(t, client.ApplyQuota(defaultCtx, volumeName, spec))
+}
+
+func TestQuotaSpecValidate(t *testing.T) {
+	assert.NoError(t, (&QuotaSpec{Hard: 10, Advisory: 5}).Validate())
+	assert.NoError(t, (&QuotaSpec{
+		Hard: 10, Soft: 8, SoftGrace: time.Minute}).Validate())
+	assert.Error(t, (&QuotaSpec{Soft: 8}).Validate())
+	assert.Error(t, (&QuotaSpec{SoftGrace: time.Minute}).Validate())
+	assert.Error(t, (&QuotaSpec{
+		Hard: 5, Soft: 8, SoftGrace: time.Minute}).Validate())
+	assert.Error(t, (&QuotaSpec{Hard: 5, Advisory: 8}).Validate())
+	assert.Error(t, (&QuotaSpec{Hard: -1}).Validate())
+}
+
+func TestQuotaSpecRoundTrip(t *testing.T) {
+	spec := &QuotaSpec{
+		Hard:             100,
+		Soft:             50,
+		SoftGrace:        time.Hour,
+		IncludeSnapshots: true,
+	}
+	var (
+		yes   = true
+		no    = false
+		quota = &apiv2.Quota{
+			IncludeSnapshots:          &yes,
+			ThresholdsIncludeOverhead: &no,
+			Enforced:                  &yes,
+			Thresholds:                spec.thresholds(),
+		}
+	)
+	assert.Equal(t, *spec, *quotaSpecFromQuota(quota))
+}
+
+func TestSetUserQuota(t *testing.T) {
+	volumeName := "test_set_user_quota"
+
+	_, err := client.CreateVolume(defaultCtx, volumeName)
+	assertNoError(t, err)
+	defer client.DeleteVolume(defaultCtx, volumeName)
+	defer client.ClearPersonaQuota(
+		defaultCtx, volumeName, apiv2.QuotaTypeUser, userPersona("root"))
+
+	spec := &QuotaSpec{Hard: 10 * 1024 * 1024}
+	assertNoError(t, client.SetUserQuota(defaultCtx, volumeName, "root", spec))
+	assertNoError(t, client.SetUserQuota(defaultCtx, volumeName, "root", spec))
+
+	actual, err := client.GetUserQuotaSpec(defaultCtx, volumeName, "root")
+	assertNoError(t, err)
+	assertNotNil(t, actual)
+	assert.Equal(t, *spec, *actual)
+
+	quotas, err := client.GetVolumeQuotas(
+		defaultCtx, volumeName, &apiv2.QuotaFilter{Type: &apiv2.PQuotaTypeUser})
+	assertNoError(t, err)
+	assertLen(t, quotas, 1)
+}
+
+func TestPersonaMatches(t *testing.T) {
+	var (
+		root   = "root"
+		admin  = "Admin"
+		actual = &apiv2.Persona{
+			ID:   &apiv2.PersonaID{ID: "0", Type: apiv2.PersonaIDTypeUID},
+			Type: &apiv2.PPersonaTypeUser,
+			Name: &root,
+		}
+	)
+	assert.True(t, personaMatches(userPersona("ROOT"), actual))
+	assert.False(t, personaMatches(groupPersona("root"), actual))
+	assert.False(t, personaMatches(userPersona(admin), actual))
+	assert.True(t, personaMatches(&apiv2.Persona{
+		ID: &apiv2.PersonaID{ID: "0", Type: apiv2.PersonaIDTypeUID}}, actual))
+	assert.False(t, personaMatches(&apiv2.Persona{
+		ID: &apiv2.PersonaID{ID: "0", Type: apiv2.PersonaIDTypeGID}}, actual))
+	assert.False(t, personaMatches(userPersona(root), nil))
+}
+
+func TestApplyQuotaEnforcesByDefault(t *testing.T) {
+	var bodies []map[string]interface{}
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			w.Write([]byte(`{"quotas":[]}`))
+		case http.MethodPost:
+			body := map[string]interface{}{}
+			assertNoError(t, json.NewDecoder(r.Body).Decode(&body))
+			bodies = append(bodies, body)
+			w.Write([]byte(`{"id":"q1"}`))
+		}
+	}, nil)
+
+	assertNoError(t, c.ApplyQuota(defaultCtx, "vol1", &QuotaSpec{Hard: 100}))
+	assertNoError(t, c.ApplyQuota(
+		defaultCtx, "vol1", &QuotaSpec{Hard: 100, AccountingOnly: true}))
+	assertLen(t, bodies, 2)
+	assert.Equal(t, true, bodies[0]["enforced"])
+	assert.Equal(t, false, bodies[1]["enforced"])
+}
@@ -0,0 +1,98 @@
+package goisilon
+
+import (
+	"context"
+
+	api "github.com/thecodeteam/goisilon/api/v2"
+)
+
+type ShareList []*api.Share
+type Share *api.Share
+
+// ListShares returns a list of all SMB shares on the cluster.
+func (c *Client) ListShares(ctx context.Context) (ShareList, error) {
+	return api.SharesList(ctx, c.API)
+}
+
+// GetShare returns the SMB share with the provided name.
+func (c *Client) GetShare(ctx context.Context, name string) (Share, error) {
+	return api.ShareInspect(ctx, c.API, name)
+}
+
+// CreateShare creates an SMB share and returns its ID.
+func (c *Client) CreateShare(
+	ctx context.Context, share *api.Share) (string, error) {
+
+	return api.ShareCreate(ctx, c.API, share)
+}
+
+// UpdateShare updates an SMB share. Only the share's non-nil fields are
+// modified.
+func (c *Client) UpdateShare(ctx context.Context, share *api.Share) error {
+	return api.ShareUpdate(ctx, c.API, share)
+}
+
+// DeleteShare deletes the SMB share with the provided name.
+func (c *Client) DeleteShare(ctx context.Context, name string) error {
+	return api.ShareDelete(ctx, c.API, name)
+}
+
+// GetSharesByVolume returns the SMB shares with a path for the provided
+// volume name.
+func (c *Client) GetSharesByVolume(
+	ctx context.Context, name string) (ShareList, error) {
+
+	shares, err := api.SharesList(ctx, c.API)
+	if err != nil {
+		return nil, err
+	}
+	var (
+		volShares ShareList
+		path      = c.API.VolumePath(name)
+	)
+	for _, s := range shares {
+		if s.Path != nil && *s.Path == path {
+			volShares = append(volShares, s)
+		}
+	}
+	return volShares, nil
+}
+
+// ShareVolume shares the volume with a given name over SMB and returns the
+// share's ID. If the volume is already shared then the ID of the first
+// existing share is returned. The share is named after the volume.
+func (c *Client) ShareVolume(ctx context.Context, name string) (string, error) {
+
+	shares, err := c.GetSharesByVolume(ctx, name)
+	if err != nil {
+		return "", err
+	}
+	if len(shares) > 0 {
+		return shares[0].ID, nil
+	}
+
+	var (
+		shareName = flatVolumeName(name)
+		path      = c.API.VolumePath(name)
+	)
+
+	return api.ShareCreate(
+		ctx, c.API,
+		&api.Share{Name: &shareName, Path: &path})
+}
+
+// UnshareVolume deletes all of the SMB shares for the volume with a given
+// name.
+func (c *Client) UnshareVolume(ctx context.Context, name string) error {
+
+	shares, err := c.GetSharesByVolume(ctx, name)
+	if err != nil {
+		return err
+	}
+	for _, s := range shares {
+		if err := api.ShareDelete(ctx, c.API, s.ID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,46 @@
+package goisilon
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	apiv2 "github.com/thecodeteam/goisilon/api/v2"
+)
+
+func TestShareVolume(t *testing.T) {
+	volumeName := "test_share_volume"
+
+	_, err := client.CreateVolume(defaultCtx, volumeName)
+	assertNoError(t, err)
+	defer client.DeleteVolume(defaultCtx, volumeName)
+
+	id, err := client.ShareVolume(defaultCtx, volumeName)
+	assertNoError(t, err)
+	defer client.UnshareVolume(defaultCtx, volumeName)
+
+	// sharing the volume again returns the existing share
+	id2, err := client.ShareVolume(defaultCtx, volumeName)
+	assertNoError(t, err)
+	assert.Equal(t, id, id2)
+
+	abe := true
+	assertNoError(t, client.UpdateShare(defaultCtx, &apiv2.Share{
+		ID: id, AccessBasedEnumeration: &abe}))
+
+	share, err := client.GetShare(defaultCtx, id)
+	assertNoError(t, err)
+	assertNotNil(t, share)
+	assert.Equal(t, client.API.VolumePath(volumeName), *share.Path)
+	assert.True(t, *share.AccessBasedEnumeration)
+
+	assertNoError(t, client.UnshareVolume(defaultCtx, volumeName))
+	shares, err := client.GetSharesByVolume(defaultCtx, volumeName)
+	assertNoError(t, err)
+	assert.Len(t, shares, 0)
+}
+
+func TestFlatVolumeName(t *testing.T) {
+	assert.Equal(t, "vol1", flatVolumeName("vol1"))
+	assert.Equal(t, "a_b", flatVolumeName("/a/b/"))
+}
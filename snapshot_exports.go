@@ -0,0 +1,185 @@
+package goisilon
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"strings"
+
+	apiv2 "github.com/thecodeteam/goisilon/api/v2"
+)
+
+// SnapshotExportOptions are the options used when exporting a snapshot.
+type SnapshotExportOptions struct {
+
+	// SubPath is the path of the sub-directory inside of the snapshot to
+	// export. If empty, the snapshot's entire path is exported.
+	SubPath string
+
+	// Clients is the list of clients that may mount the export. If empty,
+	// all clients may mount the export.
+	Clients []string
+
+	// Description is the export's description. The link to the snapshot is
+	// appended to it.
+	Description string
+}
+
+// SnapshotExportedError is returned when a snapshot cannot be removed because
+// it is still exported.
+type SnapshotExportedError struct {
+	SnapshotID int64
+	ExportIDs  []int
+}
+
+// Error returns the string representation of a SnapshotExportedError.
+func (e *SnapshotExportedError) Error() string {
+	return fmt.Sprintf(
+		"snapshot %d is exported by exports %v", e.SnapshotID, e.ExportIDs)
+}
+
+// snapshotExportTag returns the token added to the description of an export
+// created by ExportSnapshot in order to record the export's snapshot.
+func snapshotExportTag(id int64) string {
+	return fmt.Sprintf("goisilon:snapshot=%d", id)
+}
+
+func hasSnapshotExportTag(ex Export, id int64) bool {
+	if ex.Description == nil {
+		return false
+	}
+	tag := snapshotExportTag(id)
+	for _, f := range strings.Fields(*ex.Description) {
+		if f == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// SnapshotPath returns the absolute path of a snapshot's contents, which is
+// the snapshot's path rewritten to its location under the cluster's snapshot
+// directory.
+func (c *Client) SnapshotPath(snapshot Snapshot) (string, error) {
+	p := path.Clean(snapshot.Path)
+	if p != "/ifs" && !strings.HasPrefix(p, "/ifs/") {
+		return "", fmt.Errorf(
+			"snapshot path not beneath /ifs: %s", snapshot.Path)
+	}
+	return apiv2.SnapshotPath(snapshot.Name, p), nil
+}
+
+// isSnapshotExport returns a flag indicating whether or not an export
+// exports the contents of a snapshot. This is the case if one of the
+// export's paths is inside of the snapshot's root directory, or if the
+// export is linked to the snapshot by ExportSnapshot.
+func isSnapshotExport(ex Export, snapshot Snapshot) bool {
+	root := apiv2.SnapshotPath(snapshot.Name, "/ifs")
+	if ex.Paths != nil {
+		for _, p := range *ex.Paths {
+			if p = path.Clean(p); p == root || strings.HasPrefix(p, root+"/") {
+				return true
+			}
+		}
+	}
+	return hasSnapshotExportTag(ex, snapshot.Id)
+}
+
+// ExportSnapshot creates a read-only NFS export of a snapshot and returns
+// the export's ID. The export is found by GetSnapshotExports and
+// UnexportSnapshot through its path. If an export of the same path was
+// already created by ExportSnapshot then its ID is returned instead.
+func (c *Client) ExportSnapshot(
+	ctx context.Context,
+	snapshotID int64,
+	opts *SnapshotExportOptions) (int, error) {
+
+	if opts == nil {
+		opts = &SnapshotExportOptions{}
+	}
+
+	snapshot, err := c.GetSnapshotByRef(ctx, SnapshotByID(snapshotID))
+	if err != nil {
+		return 0, err
+	}
+	snapPath, err := c.SnapshotPath(snapshot)
+	if err != nil {
+		return 0, err
+	}
+	snapPath = path.Join(snapPath, opts.SubPath)
+
+	exports, err := c.GetExportsByPath(ctx, snapPath)
+	if err != nil {
+		return 0, err
+	}
+	for _, ex := range exports {
+		if hasSnapshotExportTag(ex, snapshotID) {
+			return ex.ID, nil
+		}
+	}
+
+	var (
+		readOnly    = true
+		paths       = []string{snapPath}
+		description = strings.TrimSpace(
+			opts.Description + " " + snapshotExportTag(snapshotID))
+		export = &apiv2.Export{
+			Paths:       &paths,
+			ReadOnly:    &readOnly,
+			Description: &description,
+		}
+	)
+	if len(opts.Clients) > 0 {
+		clients := normalizeExportClients(opts.Clients...)
+		export.Clients = &clients
+	}
+
+	return apiv2.ExportCreate(ctx, c.API, export)
+}
+
+// GetSnapshotExports returns the exports of a snapshot's contents. These are
+// the exports of paths inside of the snapshot as well as the exports linked
+// to the snapshot by ExportSnapshot. Since exports can only be filtered by
+// their exact paths, all of the cluster's exports are listed.
+func (c *Client) GetSnapshotExports(
+	ctx context.Context, snapshotID int64) (ExportList, error) {
+
+	snapshot, err := c.GetSnapshotByRef(ctx, SnapshotByID(snapshotID))
+	if err != nil {
+		return nil, err
+	}
+	return c.getSnapshotExports(ctx, snapshot)
+}
+
+func (c *Client) getSnapshotExports(
+	ctx context.Context, snapshot Snapshot) (ExportList, error) {
+
+	exports, err := c.GetExports(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var snapExports ExportList
+	for _, ex := range exports {
+		if isSnapshotExport(ex, snapshot) {
+			snapExports = append(snapExports, ex)
+		}
+	}
+	return snapExports, nil
+}
+
+// UnexportSnapshot deletes all of the exports of a snapshot's contents. See
+// GetSnapshotExports.
+func (c *Client) UnexportSnapshot(
+	ctx context.Context, snapshotID int64) error {
+
+	exports, err := c.GetSnapshotExports(ctx, snapshotID)
+	if err != nil {
+		return err
+	}
+	for _, ex := range exports {
+		if err := c.UnexportByID(ctx, ex.ID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,116 @@
+package goisilon
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	apiv1 "github.com/thecodeteam/goisilon/api/v1"
+	apiv2 "github.com/thecodeteam/goisilon/api/v2"
+)
+
+func TestExportSnapshot(t *testing.T) {
+	volumeName := "test_export_snapshot_volume"
+	snapshotName := "test_export_snapshot"
+
+	_, err := client.CreateVolume(defaultCtx, volumeName)
+	assertNoError(t, err)
+	defer client.DeleteVolume(defaultCtx, volumeName)
+
+	snapshot, err := client.CreateSnapshot(
+		defaultCtx, volumeName, snapshotName)
+	assertNoError(t, err)
+	defer client.RemoveSnapshotWithOptions(
+		defaultCtx, snapshot.Id, snapshotName,
+		&RemoveSnapshotOptions{UnexportSnapshot: true})
+
+	id, err := client.ExportSnapshot(defaultCtx, snapshot.Id, nil)
+	assertNoError(t, err)
+
+	ex, err := client.GetExportByID(defaultCtx, id)
+	assertNoError(t, err)
+	assertNotNil(t, ex)
+	assert.True(t, *ex.ReadOnly)
+	assert.Equal(t,
+		apiv2.VolumeSnapshotPath(client.API, snapshotName, volumeName),
+		(*ex.Paths)[0])
+
+	err = client.RemoveSnapshot(defaultCtx, snapshot.Id, snapshotName)
+	if assert.IsType(t, &SnapshotExportedError{}, err) {
+		assert.Equal(t, []int{id}, err.(*SnapshotExportedError).ExportIDs)
+	}
+
+	assertNoError(t, client.UnexportSnapshot(defaultCtx, snapshot.Id))
+	exports, err := client.GetSnapshotExports(defaultCtx, snapshot.Id)
+	assertNoError(t, err)
+	assert.Len(t, exports, 0)
+}
+
+func TestHasSnapshotExportTag(t *testing.T) {
+	desc := "nightly goisilon:snapshot=12"
+	ex := &apiv2.Export{Description: &desc}
+	assert.True(t, hasSnapshotExportTag(ex, 12))
+	assert.False(t, hasSnapshotExportTag(ex, 1))
+	assert.False(t, hasSnapshotExportTag(&apiv2.Export{}, 12))
+}
+
+func TestIsSnapshotExport(t *testing.T) {
+	var (
+		snapshot = &apiv1.IsiSnapshot{
+			Id: 12, Name: "snap1", Path: "/ifs/volumes/vol1"}
+		desc   = "renamed by an admin"
+		tagged = "goisilon:snapshot=12"
+		inside = []string{"/ifs/.snapshot/snap1/volumes/vol1/sub/"}
+		root   = []string{"/ifs/.snapshot/snap1"}
+		other  = []string{"/ifs/.snapshot/snap10/volumes/vol1"}
+		live   = []string{"/ifs/volumes/vol1"}
+	)
+	assert.True(t, isSnapshotExport(
+		&apiv2.Export{Paths: &inside, Description: &desc}, snapshot))
+	assert.True(t, isSnapshotExport(&apiv2.Export{Paths: &root}, snapshot))
+	assert.True(t, isSnapshotExport(
+		&apiv2.Export{Paths: &live, Description: &tagged}, snapshot))
+	assert.False(t, isSnapshotExport(&apiv2.Export{Paths: &other}, snapshot))
+	assert.False(t, isSnapshotExport(&apiv2.Export{Paths: &live}, snapshot))
+}
+
+func TestSnapshotPathUnderIFS(t *testing.T) {
+	p, err := client.SnapshotPath(
+		&apiv1.IsiSnapshot{Name: "snap1", Path: "/ifs/vol1"})
+	assertNoError(t, err)
+	assert.Equal(t, "/ifs/.snapshot/snap1/vol1", p)
+
+	p, err = client.SnapshotPath(&apiv1.IsiSnapshot{Name: "snap1", Path: "/ifs"})
+	assertNoError(t, err)
+	assert.Equal(t, "/ifs/.snapshot/snap1", p)
+
+	_, err = client.SnapshotPath(&apiv1.IsiSnapshot{Name: "snap1", Path: "/tmp"})
+	assertError(t, err)
+}
+
+func TestRemoveSnapshotListsExportsByPath(t *testing.T) {
+	var requests []string
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		switch {
+		case r.URL.Path == "/platform/1/snapshot/snapshots/3" &&
+			r.Method == http.MethodGet:
+			w.Write([]byte(`{"snapshots":[{"id":3,"name":"snap1",` +
+				`"path":"/ifs/volumes/vol1"}]}`))
+		case r.URL.Path == "/platform/2/protocols/nfs/exports":
+			assert.Equal(t, "/ifs/.snapshot/snap1/volumes/vol1",
+				r.URL.Query().Get("path"))
+			w.Write([]byte(`{"exports":[]}`))
+		default:
+			w.Write([]byte(`{}`))
+		}
+	}, nil)
+
+	assertNoError(t, c.RemoveSnapshot(defaultCtx, 3, ""))
+	assert.Equal(t, []string{
+		"GET /platform/1/snapshot/snapshots/3",
+		"GET /platform/2/protocols/nfs/exports",
+		"DELETE /platform/1/snapshot/snapshots/3",
+	}, requests)
+}
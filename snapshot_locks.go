@@ -0,0 +1,139 @@
+package goisilon
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	api "github.com/thecodeteam/goisilon/api/v1"
+)
+
+type SnapshotLock *api.IsiSnapshotLock
+type SnapshotAlias *api.IsiSnapshotAlias
+
+// GetSnapshotLocks returns the locks of a snapshot.
+func (c *Client) GetSnapshotLocks(
+	ctx context.Context, id int64) ([]SnapshotLock, error) {
+
+	locks, err := api.GetIsiSnapshotLocks(ctx, c.API, id)
+	if err != nil {
+		return nil, err
+	}
+	snapshotLocks := make([]SnapshotLock, len(locks))
+	for i, l := range locks {
+		snapshotLocks[i] = l
+	}
+	return snapshotLocks, nil
+}
+
+// LockSnapshot locks a snapshot so that it cannot be deleted, for example
+// while a backup reads from it, and returns the ID of the lock. If expires
+// is zero then the lock never expires.
+func (c *Client) LockSnapshot(
+	ctx context.Context,
+	id int64,
+	comment string,
+	expires time.Time) (int64, error) {
+
+	var exp int64
+	if !expires.IsZero() {
+		exp = expires.Unix()
+	}
+	return api.CreateIsiSnapshotLock(ctx, c.API, id, comment, exp)
+}
+
+// SetSnapshotLockExpiry sets when a snapshot lock expires. If expires is
+// zero then the lock no longer expires.
+func (c *Client) SetSnapshotLockExpiry(
+	ctx context.Context, id, lockID int64, expires time.Time) error {
+
+	var exp int64
+	if !expires.IsZero() {
+		exp = expires.Unix()
+	}
+	return api.UpdateIsiSnapshotLock(ctx, c.API, id, lockID, exp)
+}
+
+// UnlockSnapshot removes a lock from a snapshot.
+func (c *Client) UnlockSnapshot(
+	ctx context.Context, id, lockID int64) error {
+
+	return api.RemoveIsiSnapshotLock(ctx, c.API, id, lockID)
+}
+
+// GetSnapshotAliases returns all of the snapshot aliases on the cluster.
+func (c *Client) GetSnapshotAliases(
+	ctx context.Context) ([]SnapshotAlias, error) {
+
+	aliases, err := api.GetIsiSnapshotAliases(ctx, c.API)
+	if err != nil {
+		return nil, err
+	}
+	snapshotAliases := make([]SnapshotAlias, len(aliases))
+	for i, a := range aliases {
+		snapshotAliases[i] = a
+	}
+	return snapshotAliases, nil
+}
+
+// GetSnapshotAlias returns the snapshot alias with the provided name.
+func (c *Client) GetSnapshotAlias(
+	ctx context.Context, name string) (SnapshotAlias, error) {
+
+	return api.GetIsiSnapshotAlias(ctx, c.API, name)
+}
+
+// SetSnapshotAlias points the alias with the provided name at a snapshot,
+// creating the alias if it does not exist.
+func (c *Client) SetSnapshotAlias(
+	ctx context.Context, name string, id int64) error {
+
+	target := strconv.FormatInt(id, 10)
+	_, err := api.GetIsiSnapshotAlias(ctx, c.API, name)
+	if isNotFound(err) {
+		_, err = api.CreateIsiSnapshotAlias(ctx, c.API, name, target)
+		return err
+	}
+	if err != nil {
+		return err
+	}
+	return api.UpdateIsiSnapshotAlias(ctx, c.API, name, target)
+}
+
+// DeleteSnapshotAlias deletes the snapshot alias with the provided name.
+func (c *Client) DeleteSnapshotAlias(ctx context.Context, name string) error {
+	return api.RemoveIsiSnapshotAlias(ctx, c.API, name)
+}
+
+// AliasLatestSnapshot points the alias with the provided name at the most
+// recent snapshot of a volume and returns that snapshot.
+func (c *Client) AliasLatestSnapshot(
+	ctx context.Context, volume, name string) (Snapshot, error) {
+
+	snapshots, err := c.GetSnapshotsByPath(ctx, volume)
+	if err != nil {
+		return nil, err
+	}
+	latest := latestSnapshot(snapshots)
+	if latest == nil {
+		return nil, errors.New("volume has no snapshots: " + volume)
+	}
+	if err := c.SetSnapshotAlias(ctx, name, latest.Id); err != nil {
+		return nil, err
+	}
+	return latest, nil
+}
+
+// latestSnapshot returns the most recently created of the provided
+// snapshots, or nil if there are none.
+func latestSnapshot(snapshots SnapshotList) *api.IsiSnapshot {
+	var latest *api.IsiSnapshot
+	for _, s := range snapshots {
+		if latest == nil || s.Created > latest.Created ||
+			(s.Created == latest.Created && s.Id > latest.Id) {
+			latest = s
+		}
+	}
+	return latest
+}
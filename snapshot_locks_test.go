@@ -0,0 +1,135 @@
+package goisilon
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSnapshotLocks(t *testing.T) {
+	volumeName := "test_snapshot_locks_volume"
+	snapshotName := "test_snapshot_locks_snapshot"
+
+	_, err := client.CreateVolume(defaultCtx, volumeName)
+	assertNoError(t, err)
+	defer client.DeleteVolume(defaultCtx, volumeName)
+
+	expires := time.Now().Add(time.Hour).Truncate(time.Second)
+	snapshot, err := client.CreateSnapshotWithOptions(
+		defaultCtx, volumeName, snapshotName,
+		&CreateSnapshotOptions{Expires: expires})
+	assertNoError(t, err)
+	defer client.RemoveSnapshot(defaultCtx, snapshot.Id, snapshotName)
+
+	extended, err := client.ExtendSnapshotExpiry(
+		defaultCtx, snapshot.Id, time.Hour)
+	assertNoError(t, err)
+	assert.Equal(t, expires.Add(time.Hour).Unix(), extended.Unix())
+
+	lockID, err := client.LockSnapshot(
+		defaultCtx, snapshot.Id, "backup in progress", time.Time{})
+	assertNoError(t, err)
+
+	locks, err := client.GetSnapshotLocks(defaultCtx, snapshot.Id)
+	assertNoError(t, err)
+	assertLen(t, locks, 1)
+	assert.Equal(t, "backup in progress", locks[0].Comment)
+
+	// a locked snapshot cannot be removed
+	assert.Error(t, client.RemoveSnapshot(
+		defaultCtx, snapshot.Id, snapshotName))
+
+	assertNoError(t, client.UnlockSnapshot(defaultCtx, snapshot.Id, lockID))
+}
+
+func TestAliasLatestSnapshot(t *testing.T) {
+	volumeName := "test_alias_latest_snapshot_volume"
+	aliasName := "test_alias_latest_snapshot_alias"
+
+	_, err := client.CreateVolume(defaultCtx, volumeName)
+	assertNoError(t, err)
+	defer client.DeleteVolume(defaultCtx, volumeName)
+
+	snapshot, err := client.CreateSnapshot(defaultCtx, volumeName, "")
+	assertNoError(t, err)
+	defer client.RemoveSnapshot(defaultCtx, snapshot.Id, "")
+
+	latest, err := client.AliasLatestSnapshot(
+		defaultCtx, volumeName, aliasName)
+	assertNoError(t, err)
+	defer client.DeleteSnapshotAlias(defaultCtx, aliasName)
+	assert.Equal(t, snapshot.Id, latest.Id)
+
+	alias, err := client.GetSnapshotAlias(defaultCtx, aliasName)
+	assertNoError(t, err)
+	assert.Equal(t, snapshot.Id, alias.TargetId)
+}
+
+func TestLatestSnapshot(t *testing.T) {
+	assert.Nil(t, latestSnapshot(nil))
+	assert.Equal(t, int64(3), latestSnapshot(SnapshotList{
+		{Id: 1, Created: 100},
+		{Id: 3, Created: 200},
+		{Id: 2, Created: 200},
+		{Id: 4, Created: 150},
+	}).Id)
+}
+
+func TestSetSnapshotAliasCreatesMissingAlias(t *testing.T) {
+	var (
+		paths []string
+		body  map[string]interface{}
+	)
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.Method+" "+r.URL.EscapedPath())
+		switch r.Method {
+		case http.MethodGet:
+			// the cluster may return an empty list for a missing alias
+			w.Write([]byte(`{"aliases":[]}`))
+		case http.MethodPost:
+			assertNoError(t, json.NewDecoder(r.Body).Decode(&body))
+			w.Write([]byte(`{"id":5}`))
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}, nil)
+
+	assertNoError(t, c.SetSnapshotAlias(defaultCtx, "daily latest", 3))
+	assert.Equal(t, []string{
+		"GET /platform/1/snapshot/aliases/daily%20latest",
+		"POST /platform/1/snapshot/aliases",
+	}, paths)
+	assert.Equal(t, "daily latest", body["name"])
+	assert.Equal(t, "3", body["target"])
+}
+
+func TestSetSnapshotExpiryClearsExpiry(t *testing.T) {
+	var bodies []map[string]interface{}
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		body := map[string]interface{}{}
+		assertNoError(t, json.NewDecoder(r.Body).Decode(&body))
+		bodies = append(bodies, body)
+		w.Write([]byte(`{}`))
+	}, nil)
+
+	assertNoError(t, c.SetSnapshotExpiry(defaultCtx, 3, time.Time{}))
+	assertNoError(t, c.SetSnapshotLockExpiry(defaultCtx, 3, 1, time.Time{}))
+	assertLen(t, bodies, 2)
+	assert.Equal(t, map[string]interface{}{"expires": float64(0)}, bodies[0])
+	assert.Equal(t, map[string]interface{}{"expires": float64(0)}, bodies[1])
+}
+
+func TestExtendSnapshotExpiryNotFound(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+		w.Write([]byte(`{"snapshots":[]}`))
+	}, nil)
+
+	_, err := c.ExtendSnapshotExpiry(defaultCtx, 3, time.Hour)
+	assert.True(t, IsSnapshotNotFound(err))
+}
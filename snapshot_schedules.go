@@ -0,0 +1,264 @@
+package goisilon
+
+import (
+	"context"
+	"errors"
+	"path"
+	"strconv"
+	"time"
+
+	apiv2 "github.com/thecodeteam/goisilon/api/v2"
+)
+
+type SnapshotScheduleList []*apiv2.SnapshotSchedule
+type SnapshotSchedule *apiv2.SnapshotSchedule
+
+// SnapshotScheduleSpec is the desired configuration of a volume's snapshot
+// schedule.
+type SnapshotScheduleSpec struct {
+
+	// Name is the name of the schedule. If empty, the schedule is named
+	// after the volume. A name made only of digits is invalid since the
+	// cluster takes it for a schedule's ID.
+	Name string
+
+	// Schedule is when snapshots are taken, as a OneFS schedule such as
+	// "every 1 days at 12:00 AM".
+	Schedule string
+
+	// Pattern is the pattern from which snapshots are named, such as
+	// "%{PolicyName}_%Y-%m-%d_%H-%M". If empty,
+	// DefaultSnapshotSchedulePattern is used.
+	Pattern string
+
+	// Retention is how long snapshots are kept. It is truncated to seconds.
+	// If zero, snapshots do not expire.
+	Retention time.Duration
+
+	// Alias, if set, is the name of an alias that always refers to the most
+	// recent snapshot taken by the schedule.
+	Alias string
+}
+
+// DefaultSnapshotSchedulePattern is the pattern used for the names of
+// snapshots taken by a schedule without a pattern.
+const DefaultSnapshotSchedulePattern = "%{PolicyName}_%Y-%m-%d_%H-%M"
+
+// Validate returns an error if the SnapshotScheduleSpec cannot be applied.
+func (s *SnapshotScheduleSpec) Validate() error {
+	if s.Schedule == "" {
+		return errors.New("snapshot schedule has no schedule")
+	}
+	if isSnapshotScheduleID(s.Name) {
+		return errors.New("snapshot schedule name is a number: " + s.Name)
+	}
+	if s.Retention < 0 {
+		return errors.New("snapshot schedule retention is negative")
+	}
+	if s.Retention > 0 && s.Retention < time.Second {
+		return errors.New("snapshot schedule retention is less than a second")
+	}
+	return nil
+}
+
+// GetSnapshotSchedules returns all of the snapshot schedules on the cluster.
+func (c *Client) GetSnapshotSchedules(
+	ctx context.Context) (SnapshotScheduleList, error) {
+
+	return apiv2.SnapshotSchedulesList(ctx, c.API)
+}
+
+// GetSnapshotSchedule returns the snapshot schedule with the provided ID or
+// name.
+func (c *Client) GetSnapshotSchedule(
+	ctx context.Context, id string) (SnapshotSchedule, error) {
+
+	return apiv2.SnapshotScheduleInspect(ctx, c.API, id)
+}
+
+// CreateSnapshotSchedule creates a snapshot schedule and returns its ID.
+func (c *Client) CreateSnapshotSchedule(
+	ctx context.Context, schedule *apiv2.SnapshotSchedule) (int, error) {
+
+	return apiv2.SnapshotScheduleCreate(ctx, c.API, schedule)
+}
+
+// UpdateSnapshotSchedule updates a snapshot schedule. Only the schedule's
+// non-nil fields are modified.
+func (c *Client) UpdateSnapshotSchedule(
+	ctx context.Context, schedule *apiv2.SnapshotSchedule) error {
+
+	return apiv2.SnapshotScheduleUpdate(ctx, c.API, schedule)
+}
+
+// DeleteSnapshotSchedule deletes the snapshot schedule with the provided ID
+// or name.
+func (c *Client) DeleteSnapshotSchedule(
+	ctx context.Context, id string) error {
+
+	return apiv2.SnapshotScheduleDelete(ctx, c.API, id)
+}
+
+// GetSnapshotSchedulesByPath returns the snapshot schedules that take
+// snapshots of the provided path. These are the schedules of the path itself
+// as well as those of the path's parents, ordered from the nearest path to
+// the furthest.
+func (c *Client) GetSnapshotSchedulesByPath(
+	ctx context.Context, p string) (SnapshotScheduleList, error) {
+
+	schedules, err := apiv2.SnapshotSchedulesList(ctx, c.API)
+	if err != nil {
+		return nil, err
+	}
+	return snapshotSchedulesCovering(schedules, p), nil
+}
+
+// snapshotSchedulesCovering returns the schedules of a path and of its
+// parents, ordered from the nearest path to the furthest.
+func snapshotSchedulesCovering(
+	schedules []*apiv2.SnapshotSchedule, p string) SnapshotScheduleList {
+
+	byPath := map[string]SnapshotScheduleList{}
+	for _, s := range schedules {
+		if s.Path != nil {
+			sp := path.Clean(*s.Path)
+			byPath[sp] = append(byPath[sp], s)
+		}
+	}
+	var covering SnapshotScheduleList
+	for p = path.Clean(p); ; p = path.Dir(p) {
+		covering = append(covering, byPath[p]...)
+		if p == "/" || p == "." {
+			return covering
+		}
+	}
+}
+
+// GetVolumeSnapshotSchedules returns the snapshot schedules that take
+// snapshots of the volume with the provided name, including the schedules
+// of the volume's parent directories.
+func (c *Client) GetVolumeSnapshotSchedules(
+	ctx context.Context, name string) (SnapshotScheduleList, error) {
+
+	return c.GetSnapshotSchedulesByPath(ctx, c.API.VolumePath(name))
+}
+
+// ScheduleVolumeSnapshots creates or updates a snapshot schedule for the
+// volume with the provided name so that it matches the provided spec, and
+// returns the schedule's ID.
+func (c *Client) ScheduleVolumeSnapshots(
+	ctx context.Context,
+	name string,
+	spec *SnapshotScheduleSpec) (int, error) {
+
+	if err := spec.Validate(); err != nil {
+		return 0, err
+	}
+
+	var (
+		schedName = spec.Name
+		volPath   = c.API.VolumePath(name)
+		pattern   = spec.Pattern
+		duration  = int(spec.Retention / time.Second)
+	)
+	if schedName == "" {
+		schedName = snapshotScheduleNameForVolume(name)
+	}
+	if pattern == "" {
+		pattern = DefaultSnapshotSchedulePattern
+	}
+
+	existing, err := c.getSnapshotScheduleByName(ctx, schedName)
+	if err != nil {
+		return 0, err
+	}
+	if existing == nil {
+		schedule := &apiv2.SnapshotSchedule{
+			Name:     &schedName,
+			Path:     &volPath,
+			Pattern:  &pattern,
+			Schedule: &spec.Schedule,
+		}
+		if duration > 0 {
+			schedule.Duration = &duration
+		}
+		if spec.Alias != "" {
+			schedule.Alias = &spec.Alias
+		}
+		return apiv2.SnapshotScheduleCreate(ctx, c.API, schedule)
+	}
+	if existing.Path == nil || path.Clean(*existing.Path) != volPath {
+		return 0, errors.New(
+			"snapshot schedule belongs to another path: " + schedName)
+	}
+
+	// the retention and alias are always sent so that they can be cleared
+	if err := apiv2.SnapshotScheduleReplace(
+		ctx, c.API, existing.ID, &apiv2.SnapshotScheduleUpdateReq{
+			Pattern:  &pattern,
+			Schedule: &spec.Schedule,
+			Duration: duration,
+			Alias:    spec.Alias,
+		}); err != nil {
+		return 0, err
+	}
+	return existing.ID, nil
+}
+
+// UnscheduleVolumeSnapshots deletes the snapshot schedule created for the
+// volume with the provided name by ScheduleVolumeSnapshots without a custom
+// name. The schedules of the volume's parents are not deleted, and neither
+// are schedules with a custom name, which are deleted with
+// DeleteSnapshotSchedule.
+func (c *Client) UnscheduleVolumeSnapshots(
+	ctx context.Context, name string) error {
+
+	schedule, err := c.getSnapshotScheduleByName(
+		ctx, snapshotScheduleNameForVolume(name))
+	if err != nil || schedule == nil {
+		return err
+	}
+	if schedule.Path == nil ||
+		path.Clean(*schedule.Path) != c.API.VolumePath(name) {
+		return nil
+	}
+	return apiv2.SnapshotScheduleDelete(
+		ctx, c.API, strconv.Itoa(schedule.ID))
+}
+
+// getSnapshotScheduleByName returns the snapshot schedule with the provided
+// name, or nil if there is none.
+func (c *Client) getSnapshotScheduleByName(
+	ctx context.Context, name string) (*apiv2.SnapshotSchedule, error) {
+
+	schedule, err := apiv2.SnapshotScheduleInspect(ctx, c.API, name)
+	if isNotFound(err) {
+		return nil, nil
+	}
+	return schedule, err
+}
+
+// snapshotScheduleNameForVolume returns the name of the snapshot schedule
+// created for a volume by ScheduleVolumeSnapshots. A name made only of
+// digits would be taken for a schedule's ID, so such names are prefixed.
+func snapshotScheduleNameForVolume(name string) string {
+	n := flatVolumeName(name)
+	if isSnapshotScheduleID(n) {
+		return "volume_" + n
+	}
+	return n
+}
+
+// isSnapshotScheduleID returns a flag indicating whether or not the cluster
+// resolves the provided schedule ID or name as an ID.
+func isSnapshotScheduleID(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
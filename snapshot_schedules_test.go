@@ -0,0 +1,122 @@
+package goisilon
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	apiv2 "github.com/thecodeteam/goisilon/api/v2"
+)
+
+func TestScheduleVolumeSnapshots(t *testing.T) {
+	volumeName := "test_schedule_volume_snapshots"
+
+	_, err := client.CreateVolume(defaultCtx, volumeName)
+	assertNoError(t, err)
+	defer client.DeleteVolume(defaultCtx, volumeName)
+	defer client.UnscheduleVolumeSnapshots(defaultCtx, volumeName)
+
+	spec := &SnapshotScheduleSpec{
+		Schedule:  "every 1 days at 12:00 AM",
+		Retention: 7 * 24 * time.Hour,
+	}
+	id, err := client.ScheduleVolumeSnapshots(defaultCtx, volumeName, spec)
+	assertNoError(t, err)
+
+	// scheduling the volume again updates the same schedule
+	spec.Retention = 14 * 24 * time.Hour
+	id2, err := client.ScheduleVolumeSnapshots(defaultCtx, volumeName, spec)
+	assertNoError(t, err)
+	assert.Equal(t, id, id2)
+
+	schedule, err := client.GetSnapshotSchedule(defaultCtx, strconv.Itoa(id))
+	assertNoError(t, err)
+	assertNotNil(t, schedule)
+	assert.Equal(t, 14*24*60*60, *schedule.Duration)
+
+	schedules, err := client.GetVolumeSnapshotSchedules(defaultCtx, volumeName)
+	assertNoError(t, err)
+	if assert.NotEmpty(t, schedules) {
+		assert.Equal(t, id, schedules[0].ID)
+	}
+}
+
+func TestSnapshotSchedulesCovering(t *testing.T) {
+	newSchedule := func(id int, p string) *apiv2.SnapshotSchedule {
+		return &apiv2.SnapshotSchedule{ID: id, Path: &p}
+	}
+	schedules := []*apiv2.SnapshotSchedule{
+		newSchedule(1, "/ifs"),
+		newSchedule(2, "/ifs/volumes/vol1/"),
+		newSchedule(3, "/ifs/volumes/vol2"),
+		newSchedule(4, "/ifs/volumes"),
+		newSchedule(5, "/ifs/volumes/vol1/nested"),
+		{ID: 6},
+	}
+	var ids []int
+	for _, s := range snapshotSchedulesCovering(
+		schedules, "/ifs/volumes/vol1") {
+		ids = append(ids, s.ID)
+	}
+	assert.Equal(t, []int{2, 4, 1}, ids)
+}
+
+func TestSnapshotScheduleSpecValidate(t *testing.T) {
+	assert.NoError(t, (&SnapshotScheduleSpec{
+		Schedule: "every 1 days at 12:00 AM"}).Validate())
+	assert.Error(t, (&SnapshotScheduleSpec{}).Validate())
+	assert.Error(t, (&SnapshotScheduleSpec{
+		Schedule: "every 1 days at 12:00 AM", Retention: -1}).Validate())
+	assert.Error(t, (&SnapshotScheduleSpec{
+		Name: "123", Schedule: "every 1 days at 12:00 AM"}).Validate())
+	assert.Equal(t,
+		"a_b", snapshotScheduleNameForVolume("/a/b/"))
+	assert.Equal(t,
+		"volume_123", snapshotScheduleNameForVolume("123"))
+	assert.Equal(t,
+		"123a", snapshotScheduleNameForVolume("123a"))
+}
+
+func TestScheduleVolumeSnapshotsClearsRetentionAndAlias(t *testing.T) {
+	var body map[string]interface{}
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/platform/1/snapshot/schedules/vol1" &&
+			r.Method == http.MethodGet:
+			w.Write([]byte(`{"schedules":[{"id":7,"name":"vol1",` +
+				`"path":"/ifs/volumes/vol1","duration":604800,` +
+				`"alias":"vol1_latest"}]}`))
+		case r.URL.Path == "/platform/1/snapshot/schedules/7" &&
+			r.Method == http.MethodPut:
+			assertNoError(t, json.NewDecoder(r.Body).Decode(&body))
+			w.Write([]byte(`{}`))
+		default:
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}, nil)
+
+	id, err := c.ScheduleVolumeSnapshots(defaultCtx, "vol1",
+		&SnapshotScheduleSpec{Schedule: "every 1 days at 12:00 AM"})
+	assertNoError(t, err)
+	assert.Equal(t, 7, id)
+	assert.Equal(t, float64(0), body["duration"])
+	assert.Equal(t, "", body["alias"])
+}
+
+func TestUnscheduleVolumeSnapshotsNotFound(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/platform/1/snapshot/schedules/vol1" ||
+			r.Method != http.MethodGet {
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"errors":[{"message":"not found"}]}`))
+	}, nil)
+
+	assertNoError(t, c.UnscheduleVolumeSnapshots(defaultCtx, "vol1"))
+}
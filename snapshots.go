@@ -5,6 +5,8 @@ import (
 	"errors"
 	"fmt"
 	"path"
+	"strconv"
+	"time"
 
 	api "github.com/thecodeteam/goisilon/api/v1"
 )
@@ -38,32 +40,87 @@ func (c *Client) GetSnapshotsByPath(
 	return snapshotsWithPath, nil
 }
 
-func (c *Client) GetSnapshot(
-	ctx context.Context, id int64, name string) (Snapshot, error) {
+// SnapshotRef refers to a snapshot by its ID or by its name. If both are
+// set then the ID is used.
+type SnapshotRef struct {
+	ID   int64
+	Name string
+}
+
+// SnapshotByID returns a SnapshotRef that refers to a snapshot by its ID.
+func SnapshotByID(id int64) SnapshotRef {
+	return SnapshotRef{ID: id}
+}
 
-	// if we have an id, use it to find the snapshot
-	snapshot, err := api.GetIsiSnapshot(ctx, c.API, id)
-	if err == nil {
-		return snapshot, nil
+// SnapshotByName returns a SnapshotRef that refers to a snapshot by its
+// name.
+func SnapshotByName(name string) SnapshotRef {
+	return SnapshotRef{Name: name}
+}
+
+// String returns the snapshot's ID or name as accepted by the snapshots
+// endpoint.
+func (r SnapshotRef) String() string {
+	if r.ID > 0 {
+		return strconv.FormatInt(r.ID, 10)
 	}
+	return r.Name
+}
 
-	// there's no id or it didn't match, iterate through all snapshots and match
-	// based on name
-	if name == "" {
-		return nil, err
+// SnapshotNotFoundError is returned when a snapshot does not exist.
+type SnapshotNotFoundError struct {
+	Ref SnapshotRef
+}
+
+// Error returns the string representation of a SnapshotNotFoundError.
+func (e *SnapshotNotFoundError) Error() string {
+	return fmt.Sprintf("Snapshot doesn't exist: %s", e.Ref)
+}
+
+// IsSnapshotNotFound returns a flag indicating whether or not an error is a
+// SnapshotNotFoundError.
+func IsSnapshotNotFound(err error) bool {
+	_, ok := err.(*SnapshotNotFoundError)
+	return ok
+}
+
+// GetSnapshotByRef returns the snapshot with the provided ID or name. A
+// SnapshotNotFoundError is returned if the snapshot does not exist, or if a
+// name made only of digits refers to the ID of a snapshot with another name.
+func (c *Client) GetSnapshotByRef(
+	ctx context.Context, ref SnapshotRef) (Snapshot, error) {
+
+	if ref.ID <= 0 && ref.Name == "" {
+		return nil, errors.New("snapshot reference has no ID or name")
+	}
+	snapshot, err := api.GetIsiSnapshotByIDOrName(ctx, c.API, ref.String())
+	if isNotFound(err) || (err == nil && snapshot == nil) {
+		return nil, &SnapshotNotFoundError{Ref: ref}
 	}
-	snapshotList, err := c.GetSnapshots(ctx)
 	if err != nil {
 		return nil, err
 	}
+	// the cluster resolves a name made only of digits as an ID
+	if ref.ID <= 0 && snapshot.Name != ref.Name {
+		return nil, &SnapshotNotFoundError{Ref: ref}
+	}
+	return snapshot, nil
+}
 
-	for _, snapshot = range snapshotList {
-		if snapshot.Name == name {
-			return snapshot, nil
+// GetSnapshot returns the snapshot with the provided ID. If the ID is not
+// positive or no snapshot has the ID then the snapshot with the provided
+// name is returned. A SnapshotNotFoundError is returned if no snapshot
+// matches.
+func (c *Client) GetSnapshot(
+	ctx context.Context, id int64, name string) (Snapshot, error) {
+
+	if id > 0 {
+		snapshot, err := c.GetSnapshotByRef(ctx, SnapshotByID(id))
+		if err == nil || !IsSnapshotNotFound(err) || name == "" {
+			return snapshot, err
 		}
 	}
-
-	return nil, nil
+	return c.GetSnapshotByRef(ctx, SnapshotByName(name))
 }
 
 func (c *Client) CreateSnapshot(
@@ -72,13 +129,150 @@ func (c *Client) CreateSnapshot(
 	return api.CreateIsiSnapshot(ctx, c.API, c.API.VolumePath(path), name)
 }
 
+// CreateSnapshotOptions are the options used when creating a snapshot.
+type CreateSnapshotOptions struct {
+
+	// Expires, if not zero, is when the snapshot is deleted by the cluster.
+	Expires time.Time
+
+	// Alias, if set, is the name of an alias created for the snapshot.
+	Alias string
+}
+
+// CreateSnapshotWithOptions creates a snapshot of a volume with an optional
+// expiration time and alias.
+func (c *Client) CreateSnapshotWithOptions(
+	ctx context.Context,
+	volume, name string,
+	opts *CreateSnapshotOptions) (Snapshot, error) {
+
+	data := &api.SnapshotPath{Path: c.API.VolumePath(volume), Name: name}
+	if opts != nil {
+		if !opts.Expires.IsZero() {
+			data.Expires = opts.Expires.Unix()
+		}
+		data.Alias = opts.Alias
+	}
+	return api.CreateIsiSnapshotWithOptions(ctx, c.API, data)
+}
+
+// SetSnapshotExpiry sets when a snapshot is deleted by the cluster. If
+// expires is zero then the snapshot no longer expires.
+func (c *Client) SetSnapshotExpiry(
+	ctx context.Context, id int64, expires time.Time) error {
+
+	var exp int64
+	if !expires.IsZero() {
+		exp = expires.Unix()
+	}
+	return api.UpdateIsiSnapshot(
+		ctx, c.API, id, &api.IsiSnapshotUpdateReq{Expires: &exp})
+}
+
+// ExtendSnapshotExpiry postpones the deletion of a snapshot by the provided
+// duration and returns the new expiration time. A snapshot that does not
+// expire is set to expire the provided duration from now. A
+// SnapshotNotFoundError is returned if the snapshot does not exist.
+func (c *Client) ExtendSnapshotExpiry(
+	ctx context.Context, id int64, d time.Duration) (time.Time, error) {
+
+	snapshot, err := c.GetSnapshotByRef(ctx, SnapshotByID(id))
+	if err != nil {
+		return time.Time{}, err
+	}
+	expires := time.Now()
+	if snapshot.Expires > 0 {
+		expires = time.Unix(snapshot.Expires, 0)
+	}
+	expires = expires.Add(d)
+	if err := c.SetSnapshotExpiry(ctx, id, expires); err != nil {
+		return time.Time{}, err
+	}
+	return expires, nil
+}
+
+// RemoveSnapshotOptions are the options used when removing a snapshot.
+type RemoveSnapshotOptions struct {
+
+	// UnexportSnapshot indicates whether or not to delete the exports of the
+	// snapshot's path. If false, a snapshot whose path is still exported is
+	// not removed and a SnapshotExportedError is returned. Only the exports
+	// of the snapshot's path itself are found; exports of its
+	// sub-directories are deleted with UnexportSnapshot.
+	UnexportSnapshot bool
+}
+
+// RemoveSnapshot removes a snapshot. A snapshot whose path is still exported
+// is not removed and a SnapshotExportedError is returned. Note that this is a
+// change from earlier releases, which removed exported snapshots; pass
+// RemoveSnapshotOptions with UnexportSnapshot set to RemoveSnapshotWithOptions
+// to delete the exports as well.
 func (c *Client) RemoveSnapshot(
 	ctx context.Context, id int64, name string) error {
 
+	return c.RemoveSnapshotWithOptions(ctx, id, name, nil)
+}
+
+// RemoveSnapshotWithOptions removes a snapshot, deleting the snapshot's
+// exports first if requested.
+func (c *Client) RemoveSnapshotWithOptions(
+	ctx context.Context,
+	id int64, name string,
+	opts *RemoveSnapshotOptions) error {
+
 	snapshot, err := c.GetSnapshot(ctx, id, name)
 	if err != nil {
 		return err
 	}
+	return c.removeSnapshot(ctx, snapshot, opts)
+}
+
+// RemoveSnapshotByRef removes the snapshot with the provided ID or name,
+// deleting the snapshot's exports first if requested.
+func (c *Client) RemoveSnapshotByRef(
+	ctx context.Context,
+	ref SnapshotRef,
+	opts *RemoveSnapshotOptions) error {
+
+	snapshot, err := c.GetSnapshotByRef(ctx, ref)
+	if err != nil {
+		return err
+	}
+	return c.removeSnapshot(ctx, snapshot, opts)
+}
+
+func (c *Client) removeSnapshot(
+	ctx context.Context,
+	snapshot Snapshot,
+	opts *RemoveSnapshotOptions) error {
+
+	if opts == nil {
+		opts = &RemoveSnapshotOptions{}
+	}
+
+	// only the snapshot's own path is checked so that removing a snapshot
+	// does not list every export on the cluster
+	snapPath, err := c.SnapshotPath(snapshot)
+	if err != nil {
+		return err
+	}
+	exports, err := c.GetExportsByPath(ctx, snapPath)
+	if err != nil {
+		return err
+	}
+	if len(exports) > 0 && !opts.UnexportSnapshot {
+		exportIDs := make([]int, len(exports))
+		for i, ex := range exports {
+			exportIDs[i] = ex.ID
+		}
+		return &SnapshotExportedError{
+			SnapshotID: snapshot.Id, ExportIDs: exportIDs}
+	}
+	for _, ex := range exports {
+		if err := c.UnexportByID(ctx, ex.ID); err != nil {
+			return err
+		}
+	}
 
 	return api.RemoveIsiSnapshot(ctx, c.API, snapshot.Id)
 }
@@ -91,10 +285,6 @@ func (c *Client) CopySnapshot(
 	if err != nil {
 		return nil, err
 	}
-	if snapshot == nil {
-		return nil, errors.New(fmt.Sprintf(
-			"Snapshot doesn't exist: (%d, %s)", sourceId, sourceName))
-	}
 
 	_, err = api.CopyIsiSnapshot(
 		ctx, c.API, snapshot.Name,
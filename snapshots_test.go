@@ -1,8 +1,12 @@
 package goisilon
 
 import (
+	"errors"
 	"fmt"
+	"net/http"
 	"testing"
+
+	"github.com/stretchr/testify/assert"
 )
 
 func TestSnapshotsGet(t *testing.T) {
@@ -236,8 +240,8 @@ func TestSnapshotRemove(t *testing.T) {
 
 	// make sure the snapshot was removed
 	snapshot, err = client.GetSnapshot(defaultCtx, snapshot.Id, snapshotName)
-	if err != nil {
-		panic(err)
+	if !IsSnapshotNotFound(err) {
+		panic(fmt.Sprintf("Expected a SnapshotNotFoundError: %v", err))
 	}
 	if snapshot != nil {
 		panic(fmt.Sprintf("Snapshot (%s) was not removed.\n%+v\n", snapshotName, snapshot))
@@ -311,3 +315,60 @@ func TestSnapshotCopy(t *testing.T) {
 		panic(fmt.Sprintf("Sub directory has incorrect name.  Expected: (%s) Acutal: (%s)", destinationSubDirectory, subDirectory.Name))
 	}
 }
+
+func TestSnapshotGetByName(t *testing.T) {
+	volumeName := "test_snapshot_get_by_name_volume"
+	snapshotName := "test snapshot get by name"
+
+	_, err := client.CreateVolume(defaultCtx, volumeName)
+	assertNoError(t, err)
+	defer client.DeleteVolume(defaultCtx, volumeName)
+
+	testSnapshot, err := client.CreateSnapshot(
+		defaultCtx, volumeName, snapshotName)
+	assertNoError(t, err)
+
+	snapshot, err := client.GetSnapshotByRef(
+		defaultCtx, SnapshotByName(snapshotName))
+	assertNoError(t, err)
+	assert.Equal(t, testSnapshot.Id, snapshot.Id)
+
+	assertNoError(t, client.RemoveSnapshotByRef(
+		defaultCtx, SnapshotByName(snapshotName), nil))
+
+	_, err = client.GetSnapshotByRef(defaultCtx, SnapshotByID(testSnapshot.Id))
+	assert.True(t, IsSnapshotNotFound(err))
+
+	// removing a missing snapshot returns an error instead of panicking
+	assert.True(t, IsSnapshotNotFound(
+		client.RemoveSnapshot(defaultCtx, testSnapshot.Id, snapshotName)))
+}
+
+func TestSnapshotRef(t *testing.T) {
+	assert.Equal(t, "123", SnapshotByID(123).String())
+	assert.Equal(t, "snap1", SnapshotByName("snap1").String())
+	assert.Equal(t, "123", SnapshotRef{ID: 123, Name: "snap1"}.String())
+
+	err := error(&SnapshotNotFoundError{Ref: SnapshotByName("snap1")})
+	assert.True(t, IsSnapshotNotFound(err))
+	assert.EqualError(t, err, "Snapshot doesn't exist: snap1")
+	assert.False(t, IsSnapshotNotFound(errors.New("snap1")))
+
+	_, err = client.GetSnapshotByRef(defaultCtx, SnapshotRef{})
+	assert.Error(t, err)
+}
+
+func TestGetSnapshotByNumericName(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		// the cluster resolves "123" as the ID of another snapshot
+		w.Write([]byte(`{"snapshots":[{"id":123,"name":"other",` +
+			`"path":"/ifs/volumes/vol1"}]}`))
+	}, nil)
+
+	_, err := c.GetSnapshotByRef(defaultCtx, SnapshotByName("123"))
+	assert.True(t, IsSnapshotNotFound(err))
+
+	snapshot, err := c.GetSnapshotByRef(defaultCtx, SnapshotByID(123))
+	assertNoError(t, err)
+	assert.Equal(t, "other", snapshot.Name)
+}
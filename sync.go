@@ -0,0 +1,372 @@
+package goisilon
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	log "github.com/akutz/gournal"
+
+	apiv2 "github.com/thecodeteam/goisilon/api/v2"
+)
+
+// SyncOptions are the options used when syncing a local directory to a
+// volume.
+type SyncOptions struct {
+
+	// Workers is the number of files uploaded concurrently. If zero,
+	// ConcurrentHTTPConnections is used.
+	Workers int
+
+	// Delete indicates whether or not files and directories that exist in
+	// the volume but not in the local directory should be removed.
+	Delete bool
+
+	// DryRun indicates whether or not to only compute the transfer summary
+	// without modifying the volume.
+	DryRun bool
+}
+
+// SyncSummary is the transfer summary of a sync.
+type SyncSummary struct {
+	FilesUploaded int
+	BytesUploaded int64
+	FilesSkipped  int
+	DirsCreated   int
+	ModesUpdated  int
+	Deleted       int
+	Errors        SyncErrors
+}
+
+func (s *SyncSummary) addError(p, op string, err error) {
+	s.Errors = append(s.Errors, &SyncError{Path: p, Op: op, Err: err})
+}
+
+// syncUpload is a file that must be uploaded to the volume.
+type syncUpload struct {
+	rel  string
+	abs  string
+	size int64
+	mode os.FileMode
+}
+
+// SyncToVolume uploads the contents of a local directory to a volume. Only
+// files whose size differs from the remote file or that have been modified
+// since the remote file was written are uploaded. The local files' modes
+// are applied to the remote files and directories.
+func (c *Client) SyncToVolume(
+	ctx context.Context,
+	localDir, volumeName string,
+	opts *SyncOptions) (*SyncSummary, error) {
+
+	if opts == nil {
+		opts = &SyncOptions{}
+	}
+
+	info, err := os.Stat(localDir)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("not a directory: %s", localDir)
+	}
+
+	remote, err := c.queryVolumeTree(
+		ctx, "", volumeName, apiv2.ContainerChildrenSyncDetail)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		summary = &SyncSummary{}
+		local   = map[string]bool{}
+		dirs    []string
+		uploads []*syncUpload
+		chmods  = map[string]os.FileMode{}
+		removes []string
+	)
+
+	if err := filepath.Walk(localDir, func(
+		abs string, fi os.FileInfo, err error) error {
+
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(localDir, abs)
+		if err != nil {
+			return err
+		}
+		if rel == "." {
+			return nil
+		}
+		rel = filepath.ToSlash(rel)
+
+		rc := remote[rel]
+
+		switch {
+		case fi.IsDir():
+			local[rel] = true
+			if rc == nil {
+				dirs = append(dirs, rel)
+				return nil
+			}
+			if !isContainer(rc) {
+				removes = append(removes, rel)
+				dirs = append(dirs, rel)
+				return nil
+			}
+			if rc.Mode == nil || os.FileMode(*rc.Mode) != fi.Mode().Perm() {
+				chmods[rel] = fi.Mode().Perm()
+			}
+		case fi.Mode().IsRegular():
+			local[rel] = true
+			u := &syncUpload{
+				rel:  rel,
+				abs:  abs,
+				size: fi.Size(),
+				mode: fi.Mode().Perm(),
+			}
+			if rc == nil {
+				uploads = append(uploads, u)
+				return nil
+			}
+			if isContainer(rc) {
+				removes = append(removes, rel)
+				uploads = append(uploads, u)
+				return nil
+			}
+			if isChildModified(rc, fi) {
+				uploads = append(uploads, u)
+				return nil
+			}
+			summary.FilesSkipped++
+		default:
+			log.WithField("path", abs).Debug(
+				ctx, "sync skipping irregular file")
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	if opts.Delete {
+		for p := range remote {
+			if !local[p] {
+				removes = append(removes, p)
+			}
+		}
+	}
+	removes = topMostPaths(removes)
+
+	if opts.DryRun {
+		summary.DirsCreated = len(dirs)
+		summary.FilesUploaded = len(uploads)
+		summary.ModesUpdated = len(chmods)
+		summary.Deleted = len(removes)
+		for _, u := range uploads {
+			summary.BytesUploaded += u.size
+		}
+		return summary, nil
+	}
+
+	// remove the mismatched and extraneous paths first so that directories
+	// and files may be created in their place
+	for _, p := range removes {
+		if err := ctx.Err(); err != nil {
+			return summary, err
+		}
+		if err := apiv2.ContainerChildDelete(
+			ctx, c.API, path.Join(volumeName, p), true); err != nil {
+			summary.addError(p, "delete", err)
+			continue
+		}
+		summary.Deleted++
+	}
+
+	// the directories are created in the order they were walked so that
+	// parents always exist before their children
+	for _, p := range dirs {
+		if err := ctx.Err(); err != nil {
+			return summary, err
+		}
+		fi, err := os.Stat(filepath.Join(localDir, filepath.FromSlash(p)))
+		if err != nil {
+			summary.addError(p, "stat", err)
+			continue
+		}
+		if err := apiv2.ContainerCreateDir(
+			ctx, c.API, volumeName, p,
+			apiv2.FileMode(fi.Mode().Perm()), false, true); err != nil {
+			summary.addError(p, "mkdir", err)
+			continue
+		}
+		summary.DirsCreated++
+	}
+
+	for p, m := range chmods {
+		if err := ctx.Err(); err != nil {
+			return summary, err
+		}
+		mode := apiv2.FileMode(m)
+		if err := apiv2.ACLUpdate(
+			ctx, c.API, path.Join(volumeName, p),
+			&apiv2.ACL{
+				Authoritative: &apiv2.PAuthoritativeTypeMode,
+				Mode:          &mode,
+			}); err != nil {
+			summary.addError(p, "chmod", err)
+			continue
+		}
+		summary.ModesUpdated++
+	}
+
+	c.syncUploads(ctx, volumeName, uploads, opts.Workers, summary)
+
+	if err := ctx.Err(); err != nil {
+		return summary, err
+	}
+	if len(summary.Errors) > 0 {
+		return summary, summary.Errors
+	}
+	return summary, nil
+}
+
+func (c *Client) syncUploads(
+	ctx context.Context,
+	volumeName string,
+	uploads []*syncUpload,
+	workers int,
+	summary *SyncSummary) {
+
+	if workers <= 0 {
+		workers = ConcurrentHTTPConnections
+	}
+
+	var (
+		wg   = &sync.WaitGroup{}
+		lock = &sync.Mutex{}
+		work = make(chan *syncUpload)
+	)
+
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for u := range work {
+				err := c.syncUpload(ctx, volumeName, u)
+				lock.Lock()
+				if err != nil {
+					summary.addError(u.rel, "upload", err)
+				} else {
+					summary.FilesUploaded++
+					summary.BytesUploaded += u.size
+				}
+				lock.Unlock()
+			}
+		}()
+	}
+
+	for _, u := range uploads {
+		if ctx.Err() != nil {
+			break
+		}
+		work <- u
+	}
+	close(work)
+	wg.Wait()
+}
+
+func (c *Client) syncUpload(
+	ctx context.Context, volumeName string, u *syncUpload) error {
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	f, err := os.Open(u.abs)
+	if err != nil {
+		return err
+	}
+	return apiv2.ContainerCreateFile(
+		ctx, c.API, volumeName, u.rel, int(u.size),
+		apiv2.FileMode(u.mode), f, true)
+}
+
+// queryVolumeTree returns all of a volume's descendent files and directories
+// keyed by their paths relative to the volume. If snapshotName is not empty
+// then the volume is read from that snapshot.
+func (c *Client) queryVolumeTree(
+	ctx context.Context,
+	snapshotName, volumeName string,
+	detail []string) (map[string]*apiv2.ContainerChild, error) {
+
+	var (
+		vp       string
+		cc       <-chan *apiv2.ContainerChild
+		ec       <-chan error
+		children = map[string]*apiv2.ContainerChild{}
+	)
+
+	if snapshotName == "" {
+		vp = c.API.VolumePath(volumeName) + "/"
+		cc, ec = apiv2.ContainerChildrenGetQuery(
+			ctx, c.API, volumeName, 1000, -1, "", "", nil, detail)
+	} else {
+		vp = apiv2.VolumeSnapshotPath(c.API, snapshotName, volumeName) + "/"
+		cc, ec = apiv2.SnapshotChildrenGetQuery(
+			ctx, c.API, snapshotName, volumeName,
+			1000, -1, "", "", nil, detail)
+	}
+
+	for child := range cc {
+		if child.Path == nil || child.Name == nil {
+			continue
+		}
+		p := path.Join(*child.Path, *child.Name)
+		children[strings.TrimPrefix(p, vp)] = child
+	}
+	if err := <-ec; err != nil {
+		return nil, err
+	}
+	return children, nil
+}
+
+func isContainer(child *apiv2.ContainerChild) bool {
+	return child.Type != nil && *child.Type == "container"
+}
+
+func isChildModified(child *apiv2.ContainerChild, fi os.FileInfo) bool {
+	if child.Size == nil || int64(*child.Size) != fi.Size() {
+		return true
+	}
+	if child.Mode == nil || os.FileMode(*child.Mode) != fi.Mode().Perm() {
+		return true
+	}
+	if child.MTime == nil || fi.ModTime().Unix() > *child.MTime {
+		return true
+	}
+	return false
+}
+
+// topMostPaths returns the sorted list of paths with any path removed that
+// is a descendant of another path in the list.
+func topMostPaths(paths []string) []string {
+	sort.Strings(paths)
+
+	var (
+		top  []string
+		last string
+	)
+	for _, p := range paths {
+		if last != "" && (p == last || strings.HasPrefix(p, last+"/")) {
+			continue
+		}
+		top = append(top, p)
+		last = p
+	}
+	return top
+}
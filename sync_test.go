@@ -0,0 +1,50 @@
+package goisilon
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSyncToVolume(t *testing.T) {
+	volumeName := "test_sync_to_volume"
+
+	localDir, err := ioutil.TempDir("", "goisilon")
+	assertNoError(t, err)
+	defer os.RemoveAll(localDir)
+
+	assertNoError(t, os.MkdirAll(filepath.Join(localDir, "a", "b"), 0755))
+	assertNoError(t, ioutil.WriteFile(
+		filepath.Join(localDir, "a", "hello.txt"), []byte("hello"), 0644))
+	assertNoError(t, ioutil.WriteFile(
+		filepath.Join(localDir, "a", "b", "world.sh"), []byte("world"), 0755))
+
+	_, err = client.CreateVolume(defaultCtx, volumeName)
+	assertNoError(t, err)
+	defer client.DeleteVolume(defaultCtx, volumeName)
+
+	assertNoError(t, client.CreateVolumeDir(
+		defaultCtx, volumeName, "extraneous", 0755, false, false))
+
+	summary, err := client.SyncToVolume(
+		defaultCtx, localDir, volumeName, &SyncOptions{Delete: true})
+	assertNoError(t, err)
+	assert.Equal(t, 2, summary.DirsCreated)
+	assert.Equal(t, 2, summary.FilesUploaded)
+	assert.Equal(t, int64(10), summary.BytesUploaded)
+	assert.Equal(t, 1, summary.Deleted)
+
+	children, err := client.QueryVolumeChildren(defaultCtx, volumeName)
+	assertNoError(t, err)
+	assertLen(t, children, 4)
+
+	summary, err = client.SyncToVolume(
+		defaultCtx, localDir, volumeName, &SyncOptions{Delete: true})
+	assertNoError(t, err)
+	assert.Equal(t, 0, summary.FilesUploaded)
+	assert.Equal(t, 2, summary.FilesSkipped)
+	assert.Equal(t, 0, summary.Deleted)
+}
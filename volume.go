@@ -2,12 +2,12 @@ package goisilon
 
 import (
 	"context"
+	"fmt"
 	"os"
 	"path"
 	"strings"
 	"sync"
-
-	log "github.com/akutz/gournal"
+	"time"
 
 	apiv1 "github.com/thecodeteam/goisilon/api/v1"
 	apiv2 "github.com/thecodeteam/goisilon/api/v2"
@@ -32,7 +32,7 @@ func (c *Client) GetVolume(
 	return isiVolume, nil
 }
 
-//GetVolumes returns a list of volumes
+//GetVolumes returns a list of the volumes in the client's volumes path
 func (c *Client) GetVolumes(ctx context.Context) ([]Volume, error) {
 
 	volumes, err := apiv1.GetIsiVolumes(ctx, c.API)
@@ -47,6 +47,30 @@ func (c *Client) GetVolumes(ctx context.Context) ([]Volume, error) {
 	return isiVolumes, nil
 }
 
+// GetVolumesByRoot returns the volumes of each of the client's volume roots,
+// keyed by the name of the root. The default volumes path has an empty name.
+// Volume roots that do not exist yet are omitted.
+func (c *Client) GetVolumesByRoot(
+	ctx context.Context) (map[string][]Volume, error) {
+
+	rootVolumes := map[string][]Volume{}
+	for _, r := range c.volumeRoots() {
+		rc, err := c.WithVolumeRoot(r.name)
+		if err != nil {
+			return nil, err
+		}
+		volumes, err := rc.GetVolumes(ctx)
+		if err != nil {
+			if isNotFound(err) {
+				continue
+			}
+			return nil, err
+		}
+		rootVolumes[r.name] = volumes
+	}
+	return rootVolumes, nil
+}
+
 //CreateVolume creates a volume
 func (c *Client) CreateVolume(
 	ctx context.Context, name string) (Volume, error) {
@@ -67,40 +91,162 @@ func (c *Client) DeleteVolume(
 	return err
 }
 
-// ConcurrentHTTPConnections is the number of allowed concurrent HTTP
+// ConcurrentHTTPConnections is the default number of allowed concurrent HTTP
 // connections for API functions that attempt to send multiple API calls at
 // once.
 var ConcurrentHTTPConnections = 2
 
-func newConcurrentHTTPChan() chan bool {
-	c := make(chan bool, ConcurrentHTTPConnections)
-	for i := 0; i < ConcurrentHTTPConnections; i++ {
-		c <- true
-	}
-	return c
+// ForceDeleteOptions are the options used when force deleting a volume.
+type ForceDeleteOptions struct {
+
+	// Workers is the number of concurrent ownership updates. If zero,
+	// ConcurrentHTTPConnections is used.
+	Workers int
+
+	// MaxRetries is the number of times the ownership update of a single
+	// path is retried before the path is recorded as failed.
+	MaxRetries int
+
+	// Progress, if not nil, is invoked after each path has been processed.
+	// It is never invoked concurrently.
+	Progress func(ForceDeleteProgress)
+
+	// TreeDelete indicates whether or not to fall back to a OneFS TreeDelete
+	// job if the volume cannot be removed with the namespace API.
+	TreeDelete bool
+
+	// TreeDeletePollInterval is how often the TreeDelete job is checked for
+	// completion. If zero, five seconds is used.
+	TreeDeletePollInterval time.Duration
+}
+
+// ForceDeleteProgress describes the progress of a force delete operation.
+type ForceDeleteProgress struct {
+
+	// Path is the path that was just processed.
+	Path string
+
+	// Err is the error that occurred while processing Path, if any.
+	Err error
+
+	// Completed is the number of paths processed so far.
+	Completed int
+
+	// Failed is the number of paths that could not be processed so far.
+	Failed int
+
+	// Total is the number of paths that require processing.
+	Total int
 }
 
 // ForceDeleteVolume force deletes a volume by resetting the ownership of
 // all descendent directories to the current user prior to issuing a delete
 // call.
 func (c *Client) ForceDeleteVolume(ctx context.Context, name string) error {
+	return c.ForceDeleteVolumeWithOptions(ctx, name, nil)
+}
+
+// ForceDeleteVolumeWithOptions force deletes a volume by resetting the
+// ownership of all descendent directories to the current user prior to
+// issuing a delete call. The errors for the paths whose ownership could not
+// be reset are returned as PathErrors, followed by the error of the delete
+// call or of the TreeDelete job, if the volume could not be removed. If the
+// ownership of every path was reset then the error of the delete call or of
+// the TreeDelete job is returned as is.
+func (c *Client) ForceDeleteVolumeWithOptions(
+	ctx context.Context,
+	name string,
+	opts *ForceDeleteOptions) error {
+
+	if opts == nil {
+		opts = &ForceDeleteOptions{}
+	}
+
+	paths, err := c.foreignOwnedDirs(ctx, name)
+	if err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		return err
+	}
+
+	errs := c.resetOwnership(ctx, paths, opts)
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	err = c.DeleteVolume(ctx, name)
+	if err == nil {
+		return nil
+	}
+	op := "delete"
+	if opts.TreeDelete {
+		err = c.treeDeleteVolume(ctx, name, opts.TreeDeletePollInterval)
+		if err == nil {
+			return nil
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		op = "treedelete"
+	}
+	if len(errs) == 0 {
+		return err
+	}
+	return append(errs, &PathError{Path: name, Op: op, Err: err})
+}
+
+// foreignOwnedDirs returns the paths of a volume's descendent directories
+// that are not owned by the current user.
+func (c *Client) foreignOwnedDirs(
+	ctx context.Context, name string) ([]string, error) {
 
 	var (
-		user       = c.API.User()
-		vpl        = len(c.API.VolumesPath()) + 1
-		errs       = make(chan error)
-		queryDone  = make(chan int)
-		childPaths = make(chan string)
-		setACLWait = &sync.WaitGroup{}
-		setACLChan = newConcurrentHTTPChan()
-		setACLDone = make(chan int)
-		mode       = apiv2.FileMode(0755)
-		acl        = &apiv2.ACL{
+		user  = c.API.User()
+		vpl   = len(c.API.VolumesPath()) + 1
+		paths []string
+	)
+
+	cc, ec := apiv2.ContainerChildrenGetQuery(
+		ctx, c.API, name, 1000, -1, "container", "ASC",
+		[]string{"container_path", "name"},
+		[]string{"owner", "name", "container_path"})
+
+	for child := range cc {
+		if child.Path == nil || child.Name == nil {
+			continue
+		}
+		if child.Owner != nil && strings.EqualFold(user, *child.Owner) {
+			continue
+		}
+		paths = append(paths, path.Join(*child.Path, *child.Name)[vpl:])
+	}
+	if err := <-ec; err != nil {
+		return nil, err
+	}
+	return paths, nil
+}
+
+// resetOwnership sets the owner of the provided paths to the current user.
+func (c *Client) resetOwnership(
+	ctx context.Context,
+	paths []string,
+	opts *ForceDeleteOptions) PathErrors {
+
+	var (
+		errs     PathErrors
+		progress = ForceDeleteProgress{Total: len(paths)}
+		wg       = &sync.WaitGroup{}
+		lock     = &sync.Mutex{}
+		work     = make(chan string)
+		workers  = opts.Workers
+		mode     = apiv2.FileMode(0755)
+		acl      = &apiv2.ACL{
 			Action:        &apiv2.PActionTypeReplace,
 			Authoritative: &apiv2.PAuthoritativeTypeMode,
 			Owner: &apiv2.Persona{
 				ID: &apiv2.PersonaID{
-					ID:   user,
+					ID:   c.API.User(),
 					Type: apiv2.PersonaIDTypeUser,
 				},
 			},
@@ -108,68 +254,98 @@ func (c *Client) ForceDeleteVolume(ctx context.Context, name string) error {
 		}
 	)
 
-	go func() {
-		queryChan, queryErrs := apiv2.ContainerChildrenGetQuery(
-			ctx, c.API, name, 1000, -1, "container", "ASC",
-			[]string{"container_path", "name"},
-			[]string{"owner", "name", "container_path"})
+	if workers <= 0 {
+		workers = ConcurrentHTTPConnections
+	}
 
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
 		go func() {
-			if err := <-queryErrs; err != nil {
-				errs <- err
-				close(errs)
+			defer wg.Done()
+			for p := range work {
+				err := c.updateACLWithRetries(ctx, p, acl, opts.MaxRetries)
+				lock.Lock()
+				progress.Path = p
+				progress.Err = err
+				progress.Completed++
+				if err != nil {
+					progress.Failed++
+					errs = append(errs, &PathError{Path: p, Op: "chown", Err: err})
+				}
+				if opts.Progress != nil {
+					opts.Progress(progress)
+				}
+				lock.Unlock()
 			}
 		}()
+	}
 
-		go func() {
-			for child := range queryChan {
-				if strings.EqualFold(user, *child.Owner) {
-					continue
-				}
-				setACLWait.Add(1)
-				go func(s string) {
-					childPaths <- s
-				}(path.Join(*child.Path, *child.Name)[vpl:])
+send:
+	for _, p := range paths {
+		select {
+		case work <- p:
+		case <-ctx.Done():
+			break send
+		}
+	}
+	close(work)
+	wg.Wait()
+
+	return errs
+}
+
+func (c *Client) updateACLWithRetries(
+	ctx context.Context,
+	childPath string,
+	acl *apiv2.ACL,
+	maxRetries int) error {
+
+	var err error
+	for i := 0; i <= maxRetries; i++ {
+		if i > 0 {
+			select {
+			case <-time.After(time.Duration(i) * 100 * time.Millisecond):
+			case <-ctx.Done():
+				return ctx.Err()
 			}
-			close(queryDone)
-		}()
-	}()
-
-	go func() {
-		for childPath := range childPaths {
-			go func(childPath string) {
-				<-setACLChan
-				if err := apiv2.ACLUpdate(
-					ctx,
-					c.API,
-					childPath,
-					acl); err != nil {
-					go func(childPath string) {
-						childPaths <- childPath
-					}(childPath)
-				} else {
-					setACLWait.Done()
-				}
-				setACLChan <- true
-			}(childPath)
 		}
-	}()
+		if err = apiv2.ACLUpdate(ctx, c.API, childPath, acl); err == nil {
+			return nil
+		}
+	}
+	return err
+}
 
-	go func() {
-		<-queryDone
-		setACLWait.Wait()
-		close(setACLDone)
-	}()
+// treeDeleteVolume removes a volume with a OneFS TreeDelete job and waits
+// for the job to complete.
+func (c *Client) treeDeleteVolume(
+	ctx context.Context,
+	name string,
+	pollInterval time.Duration) error {
 
-	select {
-	case <-setACLDone:
-	case err := <-errs:
+	if pollInterval <= 0 {
+		pollInterval = 5 * time.Second
+	}
+
+	id, err := apiv1.StartIsiTreeDeleteJob(ctx, c.API, c.API.VolumePath(name))
+	if err != nil {
+		return err
+	}
+
+	for {
+		select {
+		case <-time.After(pollInterval):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		job, err := apiv1.GetIsiJob(ctx, c.API, id)
 		if err != nil {
 			return err
 		}
+		if done, err := apiv1.IsIsiJobDone(job); done {
+			return err
+		}
 	}
-
-	return c.DeleteVolume(ctx, name)
 }
 
 //CopyVolume creates a volume based on an existing volume
@@ -184,6 +360,139 @@ func (c *Client) CopyVolume(
 	return c.GetVolume(ctx, dest, dest)
 }
 
+// CopyOptions are the options used when copying a volume.
+type CopyOptions struct {
+
+	// Snapshot is the name of the snapshot from which to copy the source
+	// volume. If empty, the live volume is copied.
+	Snapshot string
+
+	// SourcePath is the path of the sub-directory inside of the source
+	// volume to copy. If empty, the entire volume is copied. The path must
+	// be relative and must not leave the volume.
+	SourcePath string
+
+	// DestinationPath is the path of the sub-directory inside of the
+	// destination volume to which the source is copied. If empty, the
+	// source is copied to the root of the destination volume. The path must
+	// be relative and must not leave the volume.
+	DestinationPath string
+
+	// Merge indicates whether or not to merge the source into an existing
+	// destination.
+	Merge bool
+
+	// ContinueOnError indicates whether or not to keep copying when
+	// individual files fail to copy.
+	ContinueOnError bool
+}
+
+// CopyFailure is a file that failed to copy.
+type CopyFailure struct {
+	Source   string
+	Target   string
+	ErrorSrc string
+	Message  string
+}
+
+// CopyFailures is a list of files that failed to copy.
+type CopyFailures []*CopyFailure
+
+// Error returns the string representation of a CopyFailures.
+func (f CopyFailures) Error() string {
+	msg := fmt.Sprintf(
+		"copy %s to %s: %s", f[0].Source, f[0].Target, f[0].Message)
+	if len(f) == 1 {
+		return msg
+	}
+	return fmt.Sprintf("%s (and %d more failures)", msg, len(f)-1)
+}
+
+// CopyVolumeWithOptions copies a volume, a snapshot of a volume, or a
+// sub-directory of either to a new or existing volume. If some of the files
+// fail to copy then the destination volume is returned along with a
+// CopyFailures error.
+func (c *Client) CopyVolumeWithOptions(
+	ctx context.Context,
+	src, dest string,
+	opts *CopyOptions) (Volume, error) {
+
+	if opts == nil {
+		opts = &CopyOptions{}
+	}
+
+	srcSubPath, err := volumeSubPath(opts.SourcePath)
+	if err != nil {
+		return nil, err
+	}
+	destSubPath, err := volumeSubPath(opts.DestinationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	srcPath := c.API.VolumePath(src)
+	if opts.Snapshot != "" {
+		srcPath = apiv2.VolumeSnapshotPath(c.API, opts.Snapshot, src)
+	}
+	srcPath = path.Join(srcPath, srcSubPath)
+
+	// the copy's target is relative to the volumes path, but the returned
+	// volume is always the destination volume itself
+	target := path.Join(dest, destSubPath)
+
+	resp, err := apiv1.CopyIsiVolumeWithOptions(
+		ctx, c.API, srcPath, target, opts.Merge, opts.ContinueOnError)
+	if err != nil {
+		return nil, err
+	}
+
+	volume, err := c.GetVolume(ctx, dest, dest)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp == nil || len(resp.CopyErrors) == 0 {
+		return volume, nil
+	}
+
+	failures := make(CopyFailures, len(resp.CopyErrors))
+	for i, e := range resp.CopyErrors {
+		failures[i] = &CopyFailure{
+			Source:   e.Source,
+			Target:   e.Target,
+			ErrorSrc: e.ErrorSrc,
+			Message:  e.Message,
+		}
+	}
+	return volume, failures
+}
+
+// flatVolumeName returns a volume's name with the separators of nested
+// volume names replaced with underscores, for naming objects such as SMB
+// shares and snapshot schedules whose names may not contain slashes.
+func flatVolumeName(name string) string {
+	return strings.Replace(strings.Trim(name, "/"), "/", "_", -1)
+}
+
+// volumeSubPath returns the cleaned form of a path relative to a volume. An
+// error is returned if the path is absolute or escapes the volume.
+func volumeSubPath(p string) (string, error) {
+	if p == "" {
+		return "", nil
+	}
+	if path.IsAbs(p) {
+		return "", fmt.Errorf("volume sub-path is absolute: %s", p)
+	}
+	cp := path.Clean(p)
+	if cp == ".." || strings.HasPrefix(cp, "../") {
+		return "", fmt.Errorf("volume sub-path escapes the volume: %s", p)
+	}
+	if cp == "." {
+		return "", nil
+	}
+	return cp, nil
+}
+
 //ExportVolume exports a volume
 func (c *Client) ExportVolume(
 	ctx context.Context, name string) (int, error) {
@@ -191,6 +500,33 @@ func (c *Client) ExportVolume(
 	return c.Export(ctx, name)
 }
 
+// ExportOptions are the options used when exporting a volume.
+type ExportOptions struct {
+
+	// Alias is the name of an NFS alias, such as "/app1", to create for the
+	// volume along with the export. If empty, no alias is created. See
+	// AliasVolume.
+	Alias string
+}
+
+// ExportVolumeWithOptions exports a volume and, if requested, creates an NFS
+// alias for it. The export's ID is returned.
+func (c *Client) ExportVolumeWithOptions(
+	ctx context.Context, name string, opts *ExportOptions) (int, error) {
+
+	id, err := c.Export(ctx, name)
+	if err != nil {
+		return 0, err
+	}
+	if opts == nil || opts.Alias == "" {
+		return id, nil
+	}
+	if _, err := c.AliasVolume(ctx, name, opts.Alias); err != nil {
+		return id, err
+	}
+	return id, nil
+}
+
 //UnexportVolume stops exporting a volume
 func (c *Client) UnexportVolume(
 	ctx context.Context, name string) error {
@@ -218,14 +554,16 @@ func (c *Client) CreateVolumeDir(
 		apiv2.FileMode(fileMode), overwrite, recursive)
 }
 
-// GetVolumeExportMap returns a map that relates Volumes to their corresponding
-// Exports. This function uses an Export's "clients" property to define the
-// relationship. The flag "includeRootClients" can be set to "true" in order to
-// also inspect the "root_clients" property of an Export when determining the
-// Volume-to-Export relationship.
+// GetVolumeExportMap returns a map that relates the names of Volumes to the
+// Exports that cover them. An Export covers a Volume if one of the Export's
+// paths is the Volume's path, or if the path is a parent of the Volume's path
+// and the Export's "all_dirs" property is enabled. A Volume may be covered by
+// multiple Exports and an Export may cover multiple Volumes. Volumes that are
+// not covered by any Export are omitted from the map. Only the volumes in the
+// client's volumes path are included; GetVolumeExportMapByRoot includes the
+// volumes of every volume root.
 func (c *Client) GetVolumeExportMap(
-	ctx context.Context,
-	includeRootClients bool) (map[Volume]Export, error) {
+	ctx context.Context) (map[string][]Export, error) {
 
 	volumes, err := c.GetVolumes(ctx)
 	if err != nil {
@@ -235,44 +573,49 @@ func (c *Client) GetVolumeExportMap(
 	if err != nil {
 		return nil, err
 	}
+	return volumeExportMap(
+		newExportIndex(exports), c.API.VolumesPath(), volumes), nil
+}
 
-	volToExpMap := map[Volume]Export{}
+// GetVolumeExportMapByRoot returns the volume export maps of each of the
+// client's volume roots, keyed by the name of the root. The default volumes
+// path has an empty name. See GetVolumeExportMap.
+func (c *Client) GetVolumeExportMapByRoot(
+	ctx context.Context) (map[string]map[string][]Export, error) {
 
-	for _, v := range volumes {
-		vp := c.API.VolumePath(v.Name)
-		for _, e := range exports {
-			if e.Clients == nil {
-				continue
-			}
-			for _, p := range *e.Clients {
-				if vp == p {
-					if _, ok := volToExpMap[v]; ok {
-						log.WithFields(map[string]interface{}{
-							"volumeName": v.Name,
-							"volumePath": vp,
-						}).Info(ctx, "vol-ex client map already defined")
-						break
-					}
-					volToExpMap[v] = e
-				}
-			}
-			if !includeRootClients || e.RootClients == nil {
-				continue
-			}
-			for _, p := range *e.RootClients {
-				if vp == p {
-					if _, ok := volToExpMap[v]; ok {
-						log.WithFields(map[string]interface{}{
-							"volumeName": v.Name,
-							"volumePath": vp,
-						}).Info(ctx, "vol-ex root client map already defined")
-						break
-					}
-					volToExpMap[v] = e
-				}
-			}
+	rootVolumes, err := c.GetVolumesByRoot(ctx)
+	if err != nil {
+		return nil, err
+	}
+	exports, err := c.GetExports(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		idx     = newExportIndex(exports)
+		rootMap = map[string]map[string][]Export{}
+	)
+	for _, r := range c.volumeRoots() {
+		if volumes, ok := rootVolumes[r.name]; ok {
+			rootMap[r.name] = volumeExportMap(idx, r.path, volumes)
 		}
 	}
+	return rootMap, nil
+}
+
+// volumeExportMap relates the names of the volumes in the provided volumes
+// path to the exports that cover them.
+func volumeExportMap(
+	idx exportIndex,
+	volumesPath string,
+	volumes []Volume) map[string][]Export {
 
-	return volToExpMap, nil
+	volToExpMap := map[string][]Export{}
+	for _, v := range volumes {
+		if ex := idx.covering(path.Join(volumesPath, v.Name)); len(ex) > 0 {
+			volToExpMap[v.Name] = ex
+		}
+	}
+	return volToExpMap
 }
@@ -0,0 +1,131 @@
+package goisilon
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path"
+
+	apiv1 "github.com/thecodeteam/goisilon/api/v1"
+	apiv2 "github.com/thecodeteam/goisilon/api/v2"
+)
+
+// QuotaHeadroomError is returned when the hard threshold of a new volume's
+// quota exceeds the space left by the quota of one of its parents.
+type QuotaHeadroomError struct {
+	Path     string
+	Headroom int64
+	Hard     int64
+}
+
+// Error returns the string representation of a QuotaHeadroomError.
+func (e *QuotaHeadroomError) Error() string {
+	return fmt.Sprintf(
+		"quota of %s has %d bytes of headroom, less than %d bytes",
+		e.Path, e.Headroom, e.Hard)
+}
+
+// CreateVolumeWithQuota creates a volume with a directory quota that
+// matches the provided QuotaSpec. Before anything is created, the quotas of
+// the volume's parents must leave enough headroom for the spec's hard
+// threshold, otherwise a QuotaHeadroomError is returned. The spec must be
+// enforced since an accounting-only quota does not limit the volume. If the
+// quota cannot be applied then the volume is deleted.
+func (c *Client) CreateVolumeWithQuota(
+	ctx context.Context, name string, spec *QuotaSpec) (Volume, error) {
+
+	if err := spec.Validate(); err != nil {
+		return nil, err
+	}
+	if spec.AccountingOnly {
+		return nil, errors.New("volume quota must be enforced")
+	}
+
+	// never roll back a volume that this call did not create
+	if _, err := apiv1.GetIsiVolume(ctx, c.API, name); err == nil {
+		return nil, errors.New("volume already exists: " + name)
+	} else if !isNotFound(err) {
+		return nil, err
+	}
+
+	if err := c.checkQuotaHeadroom(
+		ctx, c.API.VolumePath(name), spec.Hard); err != nil {
+		return nil, err
+	}
+
+	volume, err := c.CreateVolume(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := c.ApplyQuota(ctx, name, spec); err != nil {
+		if rerr := c.DeleteVolume(ctx, name); rerr != nil {
+			return nil, fmt.Errorf(
+				"%v: failed to delete volume %s: %v", err, name, rerr)
+		}
+		return nil, err
+	}
+
+	return volume, nil
+}
+
+// checkQuotaHeadroom returns a QuotaHeadroomError if the directory quota of
+// one of the provided path's parents has less space left than the provided
+// hard threshold. A hard threshold of zero is unlimited and always fits.
+func (c *Client) checkQuotaHeadroom(
+	ctx context.Context, p string, hard int64) error {
+
+	if hard == 0 {
+		return nil
+	}
+	for _, parent := range parentPaths(p) {
+		quota, err := c.findQuota(
+			ctx, parent, apiv2.QuotaTypeDirectory, nil)
+		if err != nil {
+			return err
+		}
+		if quota == nil {
+			continue
+		}
+		if headroom, ok := quotaHeadroom(quota); ok && hard > headroom {
+			return &QuotaHeadroomError{
+				Path:     parent,
+				Headroom: headroom,
+				Hard:     hard,
+			}
+		}
+	}
+	return nil
+}
+
+// quotaHeadroom returns the space left below a quota's hard threshold and
+// whether or not the quota enforces a hard threshold. Accounting-only quotas
+// do not limit their directories and so have no headroom.
+func quotaHeadroom(quota *apiv2.Quota) (int64, bool) {
+	if !boolValue(quota.Enforced) ||
+		quota.Thresholds == nil || int64Value(quota.Thresholds.Hard) == 0 {
+		return 0, false
+	}
+	var used int64
+	if quota.Usage != nil {
+		used = int64Value(quota.Usage.Logical)
+		if boolValue(quota.ThresholdsIncludeOverhead) {
+			used = int64Value(quota.Usage.Physical)
+		}
+	}
+	headroom := *quota.Thresholds.Hard - used
+	if headroom < 0 {
+		headroom = 0
+	}
+	return headroom, true
+}
+
+// parentPaths returns the parents of a path, nearest first, up to and
+// including /ifs.
+func parentPaths(p string) []string {
+	var parents []string
+	for p = path.Dir(p); p != "/" && p != "."; p = path.Dir(p) {
+		parents = append(parents, p)
+	}
+	return parents
+}
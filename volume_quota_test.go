@@ -0,0 +1,120 @@
+package goisilon
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	apiv2 "github.com/thecodeteam/goisilon/api/v2"
+)
+
+func TestCreateVolumeWithQuota(t *testing.T) {
+	volumeName := "test_create_volume_with_quota"
+
+	spec := &QuotaSpec{Hard: 10 * 1024 * 1024}
+	volume, err := client.CreateVolumeWithQuota(defaultCtx, volumeName, spec)
+	assertNoError(t, err)
+	assertNotNil(t, volume)
+	defer client.DeleteVolume(defaultCtx, volumeName)
+	defer client.ClearQuota(defaultCtx, volumeName)
+
+	actual, err := client.GetQuotaSpec(defaultCtx, volumeName)
+	assertNoError(t, err)
+	assertNotNil(t, actual)
+	assert.Equal(t, *spec, *actual)
+
+	// the volume already exists
+	_, err = client.CreateVolumeWithQuota(defaultCtx, volumeName, spec)
+	assert.Error(t, err)
+
+	// an invalid spec creates nothing
+	invalidName := volumeName + "_invalid"
+	_, err = client.CreateVolumeWithQuota(
+		defaultCtx, invalidName, &QuotaSpec{Soft: 1})
+	assert.Error(t, err)
+	_, err = client.GetVolume(defaultCtx, "", invalidName)
+	assert.Error(t, err)
+
+	// an accounting-only spec creates nothing
+	_, err = client.CreateVolumeWithQuota(
+		defaultCtx, invalidName, &QuotaSpec{Hard: 1, AccountingOnly: true})
+	assert.Error(t, err)
+	_, err = client.GetVolume(defaultCtx, "", invalidName)
+	assert.Error(t, err)
+}
+
+func TestCreateVolumeWithQuotaRollsBack(t *testing.T) {
+	var (
+		volumePath = "/namespace/ifs/volumes/vol1"
+		created    bool
+		deleted    bool
+	)
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == volumePath && r.Method == http.MethodGet:
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"errors":[{"message":"not found"}]}`))
+		case r.URL.Path == volumePath && r.Method == http.MethodPut:
+			created = true
+			w.Write([]byte(`{}`))
+		case r.URL.Path == volumePath && r.Method == http.MethodDelete:
+			deleted = true
+			w.Write([]byte(`{}`))
+		case r.URL.Path == "/platform/2/quota/quotas" &&
+			r.Method == http.MethodGet:
+			w.Write([]byte(`{"quotas":[]}`))
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+			w.Write([]byte(`{"errors":[{"message":"quota failed"}]}`))
+		}
+	}, nil)
+
+	_, err := c.CreateVolumeWithQuota(
+		defaultCtx, "vol1", &QuotaSpec{Hard: 1024})
+	assert.Error(t, err)
+	assert.True(t, created)
+	assert.True(t, deleted)
+}
+
+func TestQuotaHeadroom(t *testing.T) {
+	var (
+		yes      = true
+		no       = false
+		hard     = int64(1000)
+		logical  = int64(400)
+		physical = int64(1200)
+		quota    = &apiv2.Quota{
+			Enforced:   &yes,
+			Thresholds: &apiv2.QuotaThresholds{Hard: &hard},
+			Usage: &apiv2.QuotaUsage{
+				Logical: &logical, Physical: &physical},
+		}
+	)
+	headroom, ok := quotaHeadroom(quota)
+	assert.True(t, ok)
+	assert.Equal(t, int64(600), headroom)
+
+	quota.ThresholdsIncludeOverhead = &yes
+	headroom, ok = quotaHeadroom(quota)
+	assert.True(t, ok)
+	assert.Equal(t, int64(0), headroom)
+
+	// accounting-only quotas never limit a new volume
+	quota.Enforced = &no
+	_, ok = quotaHeadroom(quota)
+	assert.False(t, ok)
+
+	_, ok = quotaHeadroom(&apiv2.Quota{})
+	assert.False(t, ok)
+}
+
+func TestParentPaths(t *testing.T) {
+	assert.Equal(t,
+		[]string{"/ifs/volumes", "/ifs"},
+		parentPaths("/ifs/volumes/vol1"))
+	assert.Equal(t,
+		[]string{"/ifs/volumes/a", "/ifs/volumes", "/ifs"},
+		parentPaths("/ifs/volumes/a/b"))
+	assert.Empty(t, parentPaths("/ifs"))
+}
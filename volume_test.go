@@ -4,12 +4,14 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	"net/http"
 	"os"
 	"path"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 
+	"github.com/thecodeteam/goisilon/api"
 	apiv2 "github.com/thecodeteam/goisilon/api/v2"
 )
 
@@ -215,10 +217,11 @@ func TestVolumePath(*testing.T) {
 
 func TestVolumeGetExportMap(t *testing.T) {
 	// TODO: Make this more robust
-	volExMap, err := client.GetVolumeExportMap(defaultCtx, false)
+	volExMap, err := client.GetVolumeExportMap(defaultCtx)
 	assertNoError(t, err)
-	for v := range volExMap {
-		t.Logf("volName=%s, volPath=%s", v.Name, client.API.VolumePath(v.Name))
+	for v, exports := range volExMap {
+		t.Logf("volName=%s, volPath=%s, exports=%d",
+			v, client.API.VolumePath(v), len(exports))
 	}
 }
 
@@ -832,3 +835,149 @@ func (b *bufReadCloser) Read(p []byte) (n int, err error) {
 func (b *bufReadCloser) Close() error {
 	return nil
 }
+
+func TestForceDeleteVolumeWithOptions(t *testing.T) {
+	volumeName := "test_force_delete_volume_with_options"
+
+	_, err := client.CreateVolume(defaultCtx, volumeName)
+	assertNoError(t, err)
+	defer client.DeleteVolume(defaultCtx, volumeName)
+
+	assertNoError(t, client.CreateVolumeDir(
+		defaultCtx, volumeName, "a/b", 0755, false, true))
+
+	// a cancelled context must return promptly without deleting the volume
+	ctx, cancel := context.WithCancel(defaultCtx)
+	cancel()
+	err = client.ForceDeleteVolumeWithOptions(ctx, volumeName, nil)
+	assert.Equal(t, context.Canceled, err)
+	_, err = client.GetVolume(defaultCtx, volumeName, volumeName)
+	assertNoError(t, err)
+
+	var progress []ForceDeleteProgress
+	assertNoError(t, client.ForceDeleteVolumeWithOptions(
+		defaultCtx, volumeName, &ForceDeleteOptions{
+			Workers:    4,
+			MaxRetries: 1,
+			Progress: func(p ForceDeleteProgress) {
+				progress = append(progress, p)
+			},
+		}))
+	for _, p := range progress {
+		assertNoError(t, p.Err)
+	}
+
+	_, err = client.GetVolume(defaultCtx, volumeName, volumeName)
+	assertError(t, err)
+}
+
+func TestVolumeCopyWithOptions(t *testing.T) {
+	sourceVolumeName := "test_copy_with_options_source"
+	destinationVolumeName := "test_copy_with_options_destination"
+
+	_, err := client.CreateVolume(defaultCtx, sourceVolumeName)
+	assertNoError(t, err)
+	defer client.DeleteVolume(defaultCtx, sourceVolumeName)
+	assertNoError(t, client.CreateVolumeDir(
+		defaultCtx, sourceVolumeName, "sub/dir", 0755, false, true))
+
+	_, err = client.CreateVolume(defaultCtx, destinationVolumeName)
+	assertNoError(t, err)
+	defer client.DeleteVolume(defaultCtx, destinationVolumeName)
+	assertNoError(t, client.CreateVolumeDir(
+		defaultCtx, destinationVolumeName, "existing", 0755, false, false))
+
+	// merge the sub-directory of the source into the existing destination
+	_, err = client.CopyVolumeWithOptions(
+		defaultCtx, sourceVolumeName, destinationVolumeName,
+		&CopyOptions{
+			SourcePath:      "sub",
+			Merge:           true,
+			ContinueOnError: true,
+		})
+	assertNoError(t, err)
+
+	children, err := client.QueryVolumeChildren(
+		defaultCtx, destinationVolumeName)
+	assertNoError(t, err)
+	assertLen(t, children, 2)
+	vp := client.API.VolumePath(destinationVolumeName)
+	assertNotNil(t, children[path.Join(vp, "dir")])
+	assertNotNil(t, children[path.Join(vp, "existing")])
+}
+
+func TestVolumeSubPath(t *testing.T) {
+	for p, exp := range map[string]string{
+		"":          "",
+		".":         "",
+		"sub":       "sub",
+		"sub/dir/":  "sub/dir",
+		"a/../b":    "b",
+		"a/b/../..": "",
+	} {
+		sp, err := volumeSubPath(p)
+		assertNoError(t, err)
+		if sp != exp {
+			t.Fatalf("volumeSubPath(%q) = %q, expected %q", p, sp, exp)
+		}
+	}
+	for _, p := range []string{"/ifs", "..", "../..", "a/../../b"} {
+		_, err := volumeSubPath(p)
+		assertError(t, err)
+	}
+}
+
+func TestVolumeGetExportMapByRoot(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/namespace/ifs/volumes":
+			w.Write([]byte(`{"children":[{"name":"vol1"},{"name":"vol2"}]}`))
+		case "/namespace/ifs/gold/volumes":
+			w.Write([]byte(`{"children":[{"name":"vol1"}]}`))
+		case "/platform/2/protocols/nfs/exports":
+			w.Write([]byte(`{"exports":[
+				{"id":1,"paths":["/ifs/volumes/vol1"]},
+				{"id":2,"paths":["/ifs/gold"],"all_dirs":true}]}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"errors":[{"message":"not found"}]}`))
+		}
+	}, &api.ClientOptions{
+		VolumeRoots: map[string]string{
+			"gold":   "/ifs/gold/volumes",
+			"silver": "/ifs/silver/volumes",
+		},
+	})
+
+	rootMap, err := c.GetVolumeExportMapByRoot(defaultCtx)
+	assertNoError(t, err)
+	assertLen(t, rootMap, 2)
+	assertLen(t, rootMap[""], 1)
+	assertLen(t, rootMap[""]["vol1"], 1)
+	assert.Equal(t, 1, rootMap[""]["vol1"][0].ID)
+	assertLen(t, rootMap["gold"], 1)
+	assertLen(t, rootMap["gold"]["vol1"], 1)
+	assert.Equal(t, 2, rootMap["gold"]["vol1"][0].ID)
+
+	gold, err := c.WithVolumeRoot("gold")
+	assertNoError(t, err)
+	volToExpMap, err := gold.GetVolumeExportMap(defaultCtx)
+	assertNoError(t, err)
+	assert.Equal(t, rootMap["gold"], volToExpMap)
+}
+
+func TestForceDeleteVolumeReturnsDeleteError(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			w.Write([]byte(`{"children":[]}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"errors":[{"message":"not found"}]}`))
+		}
+	}, nil)
+
+	// without any ownership errors the delete error is not wrapped
+	err := c.ForceDeleteVolume(defaultCtx, "vol1")
+	assert.True(t, isNotFound(err), "%#v", err)
+}